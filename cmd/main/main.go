@@ -23,5 +23,9 @@ func main() {
 	if err != nil {
 		log.Fatalf("could not load persisted graph: %v", err)
 	}
-	log.Printf("loaded graph with nodes: %v", g2.Nodes())
+	var ids []string
+	for id := range g2.AllNodes() {
+		ids = append(ids, id)
+	}
+	log.Printf("loaded graph with nodes: %v", ids)
 }
@@ -0,0 +1,264 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// AllPairsAlgorithm selects how AllPairsShortestPaths computes its matrix.
+type AllPairsAlgorithm int
+
+const (
+	// AutoAllPairs picks FloydWarshall for dense graphs and Johnson for
+	// sparse ones.
+	AutoAllPairs AllPairsAlgorithm = iota
+	// FloydWarshall runs in O(V³) time, which suits dense graphs.
+	FloydWarshall
+	// Johnson reweights the edges with Bellman-Ford and then runs Dijkstra's
+	// algorithm from every node, in O(VE log V) time, which suits sparse
+	// graphs.
+	Johnson
+)
+
+func (a AllPairsAlgorithm) String() string {
+	switch a {
+	case AutoAllPairs:
+		return "auto"
+	case FloydWarshall:
+		return "floyd-warshall"
+	case Johnson:
+		return "johnson"
+	default:
+		return fmt.Sprintf("AllPairsAlgorithm(%d)", int(a))
+	}
+}
+
+// DistanceMatrix holds the shortest paths between every pair of nodes.
+// Distances[i][j] is the weight of the shortest path from Nodes[i] to
+// Nodes[j], or +Inf if there is none. Nodes are sorted by ID.
+type DistanceMatrix struct {
+	Nodes     []string
+	Distances [][]float64
+	index     map[string]int
+	// prev[i][j] is the last edge of the shortest path from Nodes[i] to
+	// Nodes[j].
+	prev [][]*Edge
+}
+
+func newDistanceMatrix(nodes []string) *DistanceMatrix {
+	m := &DistanceMatrix{
+		Nodes:     nodes,
+		Distances: make([][]float64, len(nodes)),
+		index:     make(map[string]int, len(nodes)),
+		prev:      make([][]*Edge, len(nodes)),
+	}
+	for i, id := range nodes {
+		m.index[id] = i
+		m.Distances[i] = make([]float64, len(nodes))
+		for j := range m.Distances[i] {
+			m.Distances[i][j] = math.Inf(1)
+		}
+		m.Distances[i][i] = 0
+		m.prev[i] = make([]*Edge, len(nodes))
+	}
+	return m
+}
+
+// Distance returns the weight of the shortest path from one node to another,
+// or +Inf if there is none.
+func (m *DistanceMatrix) Distance(from, to string) (float64, error) {
+	i, ok := m.index[from]
+	if !ok {
+		return 0, fmt.Errorf("node %q not found", from)
+	}
+	j, ok := m.index[to]
+	if !ok {
+		return 0, fmt.Errorf("node %q not found", to)
+	}
+	return m.Distances[i][j], nil
+}
+
+// Path reconstructs the shortest path from one node to another.
+func (m *DistanceMatrix) Path(from, to string) (*Path, error) {
+	d, err := m.Distance(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(d, 1) {
+		return nil, fmt.Errorf("no path found from %q to %q", from, to)
+	}
+
+	i := m.index[from]
+	path := &Path{Nodes: []string{to}, Weight: d}
+	for current := to; current != from; {
+		edge := m.prev[i][m.index[current]]
+		current = edge.Other(current)
+		path.Nodes = append(path.Nodes, current)
+		path.Edges = append(path.Edges, edge.clone())
+	}
+	reverse(path.Nodes)
+	reverse(path.Edges)
+	return path, nil
+}
+
+func reverse[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// AllPairsShortestPaths computes the shortest paths between every pair of
+// nodes with the given algorithm. Negative weights are allowed, but the
+// computation fails with a *NegativeCycleError if the graph has a negative
+// cycle. The matrix takes O(V²) memory, so it is meant for small and medium
+// graphs.
+func (s *store) AllPairsShortestPaths(alg AllPairsAlgorithm) (*DistanceMatrix, error) {
+	return s.AllPairsShortestPathsContext(context.Background(), alg)
+}
+
+// AllPairsShortestPathsContext is AllPairsShortestPaths that gives up with the
+// context's error once the context is done, or with ErrVisitLimit once it
+// handles more nodes than allowed.
+func (s *store) AllPairsShortestPathsContext(ctx context.Context, alg AllPairsAlgorithm) (*DistanceMatrix, error) {
+	nodes := make([]string, 0, len(s.nodes.m))
+	for id := range s.nodes.m {
+		nodes = append(nodes, id)
+	}
+	sort.Strings(nodes)
+
+	if alg == AutoAllPairs {
+		// Johnson wins when E log V is well below V²
+		v := float64(len(nodes))
+		if float64(len(s.edges.m))*math.Log2(v+1) < v*v {
+			alg = Johnson
+		} else {
+			alg = FloydWarshall
+		}
+	}
+
+	search := s.newSearch(ctx)
+	switch alg {
+	case FloydWarshall:
+		return s.floydWarshall(search, nodes)
+	case Johnson:
+		return s.johnson(search, nodes)
+	default:
+		return nil, fmt.Errorf("unknown all pairs algorithm %v", alg)
+	}
+}
+
+func (s *store) floydWarshall(search *search, nodes []string) (*DistanceMatrix, error) {
+	m := newDistanceMatrix(nodes)
+	dist, prev := m.Distances, m.prev
+
+	link := func(edge *Edge, from string) {
+		i, j := m.index[from], m.index[edge.Other(from)]
+		if edge.Weight < dist[i][j] {
+			dist[i][j] = edge.Weight
+			prev[i][j] = edge
+		}
+	}
+	for _, edge := range sortedEdges(s.edges.m) {
+		link(edge, edge.From)
+		if !edge.Directed {
+			link(edge, edge.To)
+		}
+	}
+
+	for k := range nodes {
+		if err := search.visit(); err != nil {
+			return nil, err
+		}
+		for i := range nodes {
+			if math.IsInf(dist[i][k], 1) {
+				continue
+			}
+			if err := search.step(); err != nil {
+				return nil, err
+			}
+			for j := range nodes {
+				scale := math.Max(math.Abs(dist[i][k]), math.Abs(dist[k][j]))
+				if alt := dist[i][k] + dist[k][j]; shorter(alt, dist[i][j], scale) {
+					dist[i][j] = alt
+					prev[i][j] = prev[k][j]
+				}
+			}
+		}
+	}
+
+	// A node on a negative cycle has a negative path to itself
+	for i := range nodes {
+		if dist[i][i] < 0 {
+			return nil, &NegativeCycleError{Cycle: m.cycle(i)}
+		}
+	}
+	return m, nil
+}
+
+// cycle follows the last edges of the paths from Nodes[i] back from Nodes[i]
+// until they lead round to a node already passed, and returns the cycle they
+// went round. Once Nodes[i] has a negative path to itself, that cycle is
+// negative.
+func (m *DistanceMatrix) cycle(i int) *Path {
+	passed := make(map[string]int)
+	nodes := []string{m.Nodes[i]}
+	var edges []*Edge
+	for current := m.Nodes[i]; ; {
+		passed[current] = len(edges)
+		edge := m.prev[i][m.index[current]]
+		current = edge.Other(current)
+		edges = append(edges, edge)
+		nodes = append(nodes, current)
+		if start, ok := passed[current]; ok {
+			nodes, edges = nodes[start:], edges[start:]
+			break
+		}
+	}
+
+	// The edges were followed backwards
+	reverse(nodes)
+	reverse(edges)
+	cycle := &Path{Nodes: nodes}
+	for _, edge := range edges {
+		cycle.Edges = append(cycle.Edges, edge.clone())
+		cycle.Weight += edge.Weight
+	}
+	return cycle
+}
+
+func (s *store) johnson(search *search, nodes []string) (*DistanceMatrix, error) {
+	// Bellman-Ford from a virtual source joined to every node by an edge of
+	// weight zero gives each node a potential h that makes every reweighted
+	// edge non-negative
+	h := &PathTree{Distances: make(map[string]float64, len(nodes)), Predecessors: make(map[string]*Edge)}
+	for _, id := range nodes {
+		h.Distances[id] = 0
+	}
+	if err := s.bellmanFord(search.unlimited(), h); err != nil {
+		return nil, err
+	}
+	potential := h.Distances
+	weight := func(edge *Edge, from string) (float64, bool) {
+		// Rounding can leave a reweighted edge just below zero
+		return math.Max(0, edge.Weight+potential[from]-potential[edge.Other(from)]), true
+	}
+
+	m := newDistanceMatrix(nodes)
+	for i, source := range nodes {
+		if err := search.visit(); err != nil {
+			return nil, err
+		}
+		tree, err := s.dijkstra(search.unlimited(), source, "", weight)
+		if err != nil {
+			return nil, err
+		}
+		for to, d := range tree.Distances {
+			j := m.index[to]
+			m.Distances[i][j] = d - potential[source] + potential[to]
+			m.prev[i][j] = tree.Predecessors[to]
+		}
+	}
+	return m, nil
+}
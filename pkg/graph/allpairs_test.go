@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestAllPairsMatchesShortestPath tests both algorithms against Dijkstra on every pair
+func TestAllPairsMatchesShortestPath(t *testing.T) {
+	g := newGrid(t, 5)
+
+	for _, alg := range []AllPairsAlgorithm{FloydWarshall, Johnson, AutoAllPairs} {
+		m, err := g.AllPairsShortestPaths(alg)
+		if err != nil {
+			t.Fatalf("AllPairsShortestPaths(%v) failed: %v", alg, err)
+		}
+		for _, from := range m.Nodes {
+			for _, to := range m.Nodes {
+				_, want, err := g.ShortestPath(from, to)
+				if err != nil {
+					t.Fatalf("ShortestPath failed: %v", err)
+				}
+				got, _ := m.Distance(from, to)
+				if math.Abs(got-want) > 1e-9 {
+					t.Fatalf("%v: expected distance %f from %s to %s, got %f", alg, want, from, to, got)
+				}
+				path, err := m.Path(from, to)
+				if err != nil {
+					t.Fatalf("%v: failed to reconstruct path: %v", alg, err)
+				}
+				sum := 0.0
+				for _, edge := range path.Edges {
+					sum += edge.Weight
+				}
+				if path.Nodes[0] != from || path.Nodes[len(path.Nodes)-1] != to || math.Abs(sum-want) > 1e-9 {
+					t.Fatalf("%v: expected a path from %s to %s of weight %f, got %v of weight %f", alg, from, to, want, path.Nodes, sum)
+				}
+			}
+		}
+	}
+}
+
+// TestAllPairsNegativeWeights tests both algorithms with negative weights and unreachable pairs
+func TestAllPairsNegativeWeights(t *testing.T) {
+	g := newArbitrageGraph(t)
+
+	for _, alg := range []AllPairsAlgorithm{FloydWarshall, Johnson} {
+		m, err := g.AllPairsShortestPaths(alg)
+		if err != nil {
+			t.Fatalf("AllPairsShortestPaths(%v) failed: %v", alg, err)
+		}
+		if d, _ := m.Distance("A", "D"); d != 2.0 {
+			t.Errorf("%v: expected distance 2 from A to D, got %f", alg, d)
+		}
+		path, err := m.Path("A", "D")
+		if err != nil {
+			t.Fatalf("%v: failed to reconstruct path: %v", alg, err)
+		}
+		if !equalIDs(path.Nodes, []string{"A", "B", "C", "D"}) || path.Weight != 2.0 {
+			t.Errorf("%v: expected path [A B C D] of weight 2, got %v of weight %f", alg, path.Nodes, path.Weight)
+		}
+		if d, _ := m.Distance("D", "A"); !math.IsInf(d, 1) {
+			t.Errorf("%v: expected no path from D to A, got %f", alg, d)
+		}
+		if _, err := m.Path("D", "A"); err == nil {
+			t.Errorf("%v: expected error for a missing path", alg)
+		}
+		if _, err := m.Distance("A", "nowhere"); err == nil {
+			t.Errorf("%v: expected error for a missing node", alg)
+		}
+	}
+
+	g.AddEdge(&Edge{ID: "e5", From: "D", To: "B", Weight: 1.0})
+	for _, alg := range []AllPairsAlgorithm{FloydWarshall, Johnson} {
+		_, err := g.AllPairsShortestPaths(alg)
+		var cycleErr *NegativeCycleError
+		if !errors.As(err, &cycleErr) {
+			t.Errorf("%v: expected a negative cycle error, got %v", alg, err)
+		} else if cycleErr.Cycle.Weight != -1.0 {
+			t.Errorf("%v: expected cycle weight -1, got %f", alg, cycleErr.Cycle.Weight)
+		}
+	}
+}
+
+// TestAllPairsSmallNegativeCycle tests that both algorithms report a negative
+// cycle that is small next to its weights, and agree on it
+func TestAllPairsSmallNegativeCycle(t *testing.T) {
+	g := buildGraph(t, nodesWithIDs("A", "B"), []*Edge{
+		{ID: "e1", From: "A", To: "B", Weight: 1e4},
+		{ID: "e2", From: "B", To: "A", Weight: -1e4 - 1e-6},
+	})
+
+	for _, alg := range []AllPairsAlgorithm{FloydWarshall, Johnson} {
+		m, err := g.AllPairsShortestPaths(alg)
+		var cycleErr *NegativeCycleError
+		if !errors.As(err, &cycleErr) {
+			t.Errorf("%v: expected a negative cycle error, got %v (%v)", alg, err, m)
+			continue
+		}
+		cycle := cycleErr.Cycle
+		if len(cycle.Edges) != 2 || cycle.Nodes[0] != cycle.Nodes[2] || cycle.Weight >= 0 {
+			t.Errorf("%v: expected a negative cycle of 2 edges, got %v of weight %v", alg, cycle.Nodes, cycle.Weight)
+		}
+	}
+}
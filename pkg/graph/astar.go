@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+	"math"
+)
+
+// earthRadius is the mean radius of the Earth in meters.
+const earthRadius = 6371008.8
+
+// AStar finds the shortest path from source to target like ShortestPath, but
+// lets heuristic steer the search towards the target. heuristic(a, b) must
+// estimate the distance from node a to node b without overestimating it, in
+// which case the path found is a shortest one. The nodes passed to heuristic
+// are shared with the graph and must not be modified.
+func (s *store) AStar(source, target string, heuristic func(a, b *Node) float64) ([]string, float64, error) {
+	return s.AStarContext(context.Background(), source, target, heuristic)
+}
+
+// AStarContext is AStar that gives up with the context's error once the
+// context is done, or with ErrVisitLimit once it settles more nodes than
+// allowed.
+func (s *store) AStarContext(ctx context.Context, source, target string, heuristic func(a, b *Node) float64) ([]string, float64, error) {
+	return s.shortestPath(ctx, source, target, heuristic)
+}
+
+// EuclideanHeuristic returns an AStar heuristic giving the straight-line
+// distance between nodes whose coordinates are stored in the xKey and yKey
+// properties. It is admissible when no edge weighs less than the distance
+// between its endpoints. Nodes without numeric coordinates are estimated to
+// be zero away.
+func EuclideanHeuristic(xKey, yKey string) func(a, b *Node) float64 {
+	return func(a, b *Node) float64 {
+		ax, ay, okA := coordinates(a, xKey, yKey)
+		bx, by, okB := coordinates(b, xKey, yKey)
+		if !okA || !okB {
+			return 0
+		}
+		return math.Hypot(ax-bx, ay-by)
+	}
+}
+
+// HaversineHeuristic returns an AStar heuristic giving the great-circle
+// distance in meters between nodes whose latitude and longitude, in degrees,
+// are stored in the latKey and lonKey properties. It is admissible when edge
+// weights are distances in meters along the Earth's surface. Nodes without
+// numeric coordinates are estimated to be zero away.
+func HaversineHeuristic(latKey, lonKey string) func(a, b *Node) float64 {
+	return func(a, b *Node) float64 {
+		lat1, lon1, okA := coordinates(a, latKey, lonKey)
+		lat2, lon2, okB := coordinates(b, latKey, lonKey)
+		if !okA || !okB {
+			return 0
+		}
+		lat1, lon1 = lat1*math.Pi/180, lon1*math.Pi/180
+		lat2, lon2 = lat2*math.Pi/180, lon2*math.Pi/180
+		h := math.Pow(math.Sin((lat2-lat1)/2), 2) +
+			math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin((lon2-lon1)/2), 2)
+		return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+	}
+}
+
+// coordinates reads a pair of numeric properties from a node.
+func coordinates(node *Node, xKey, yKey string) (float64, float64, bool) {
+	x, okX := normalizeValue(node.Properties[xKey])
+	y, okY := normalizeValue(node.Properties[yKey])
+	if !okX || !okY || x.kind != numberValue || y.kind != numberValue {
+		return 0, 0, false
+	}
+	return x.num, y.num, true
+}
@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// newGrid builds a size x size grid of nodes with x and y coordinates, joined
+// both ways to their right and lower neighbors by edges weighing at least
+// their length
+func newGrid(t testing.TB, size int, opts ...Option) Graph {
+	t.Helper()
+	id := func(x, y int) string { return fmt.Sprintf("%d,%d", x, y) }
+	var nodes []*Node
+	var edges []*Edge
+	link := func(from, to string, weight float64) {
+		edges = append(edges, &Edge{ID: fmt.Sprintf("e%d", len(edges)+1), From: from, To: to, Weight: weight})
+	}
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			nodes = append(nodes, &Node{ID: id(x, y), Properties: map[string]any{"x": x, "y": y}})
+		}
+	}
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			// Vary the weights so that the shortest paths are not obvious
+			weight := 1.0 + float64((x*7+y*3)%5)/2
+			if x+1 < size {
+				link(id(x, y), id(x+1, y), weight)
+				link(id(x+1, y), id(x, y), weight)
+			}
+			if y+1 < size {
+				link(id(x, y), id(x, y+1), weight)
+				link(id(x, y+1), id(x, y), weight)
+			}
+		}
+	}
+	return buildGraph(t, nodes, edges, opts...)
+}
+
+// TestAStarMatchesShortestPath tests that A* with an admissible heuristic finds shortest paths
+func TestAStarMatchesShortestPath(t *testing.T) {
+	g := newGrid(t, 8)
+	heuristic := EuclideanHeuristic("x", "y")
+
+	for _, pair := range [][2]string{{"0,0", "7,7"}, {"7,0", "0,7"}, {"3,4", "5,1"}, {"2,2", "2,2"}} {
+		_, want, err := g.ShortestPath(pair[0], pair[1])
+		if err != nil {
+			t.Fatalf("ShortestPath failed: %v", err)
+		}
+		path, got, err := g.AStar(pair[0], pair[1], heuristic)
+		if err != nil {
+			t.Fatalf("AStar failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected A* distance %f from %s to %s, got %f", want, pair[0], pair[1], got)
+		}
+		if path[0] != pair[0] || path[len(path)-1] != pair[1] {
+			t.Errorf("Expected path from %s to %s, got %v", pair[0], pair[1], path)
+		}
+	}
+}
+
+// TestAStarExploresLess tests that the heuristic narrows the search
+func TestAStarExploresLess(t *testing.T) {
+	g := newGrid(t, 20, WithVisitLimit(200))
+
+	if _, _, err := g.ShortestPath("0,0", "0,19"); !errors.Is(err, ErrVisitLimit) {
+		t.Errorf("Expected Dijkstra to exceed the visit limit, got %v", err)
+	}
+	if _, _, err := g.AStar("0,0", "0,19", EuclideanHeuristic("x", "y")); err != nil {
+		t.Errorf("Expected A* to stay within the visit limit: %v", err)
+	}
+	if _, _, err := g.AStar("0,0", "nowhere", EuclideanHeuristic("x", "y")); err == nil {
+		t.Errorf("Expected error for a missing target")
+	}
+}
+
+// TestHaversineHeuristic tests great-circle distances and their use for routing
+func TestHaversineHeuristic(t *testing.T) {
+	london := &Node{ID: "london", Properties: map[string]any{"lat": 51.5074, "lon": -0.1278}}
+	paris := &Node{ID: "paris", Properties: map[string]any{"lat": 48.8566, "lon": 2.3522}}
+	brussels := &Node{ID: "brussels", Properties: map[string]any{"lat": 50.8503, "lon": 4.3517}}
+
+	heuristic := HaversineHeuristic("lat", "lon")
+	if d := heuristic(london, paris); math.Abs(d-343.5e3) > 1e3 {
+		t.Errorf("Expected London to Paris to be about 343.5km, got %fm", d)
+	}
+	if d := heuristic(london, &Node{ID: "nowhere"}); d != 0 {
+		t.Errorf("Expected nodes without coordinates to be 0 away, got %f", d)
+	}
+
+	g := NewGraph(WithDirectedness(UndirectedGraph))
+	g.AddNode(london)
+	g.AddNode(paris)
+	g.AddNode(brussels)
+	g.AddEdge(&Edge{ID: "e1", From: "london", To: "paris", Weight: 460e3})
+	g.AddEdge(&Edge{ID: "e2", From: "london", To: "brussels", Weight: 370e3})
+	g.AddEdge(&Edge{ID: "e3", From: "brussels", To: "paris", Weight: 300e3})
+
+	_, want, _ := g.ShortestPath("london", "paris")
+	path, got, err := g.AStar("london", "paris", heuristic)
+	if err != nil {
+		t.Fatalf("AStar failed: %v", err)
+	}
+	if got != want || !equalIDs(path, []string{"london", "paris"}) {
+		t.Errorf("Expected [london paris] with distance %f, got %v with %f", want, path, got)
+	}
+}
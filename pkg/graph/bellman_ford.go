@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrNegativeWeight is returned by the algorithms that cannot handle edges of
+// negative weight, such as ShortestPath, when the graph has one. BellmanFord
+// handles them.
+var ErrNegativeWeight = errors.New("graph has edges with negative weights")
+
+// relativeTolerance is how much shorter, relative to the weights it adds up, a
+// path must be for the algorithms that allow negative weights to take it.
+// Without it, rounding makes some cycles of weight zero, such as
+// 0.1 + 0.7 - 0.8, look negative. Scaling it by the weights rather than the
+// distance keeps it from hiding genuine negative cycles on long paths.
+const relativeTolerance = 1e-12
+
+// shorter reports whether a path of length alt, found by adding up weights of
+// at most magnitude scale, is shorter than one of length old by more than
+// rounding error.
+func shorter(alt, old, scale float64) bool {
+	if math.IsInf(old, 1) {
+		return alt < old
+	}
+	return alt < old-relativeTolerance*scale
+}
+
+// NegativeCycleError is returned when a negative cycle is reachable from the
+// source of a search, so that no shortest paths exist. Cycle starts and ends
+// at the same node.
+type NegativeCycleError struct {
+	Cycle *Path
+}
+
+func (e *NegativeCycleError) Error() string {
+	return fmt.Sprintf("negative cycle %s of weight %v", strings.Join(e.Cycle.Nodes, " -> "), e.Cycle.Weight)
+}
+
+// BellmanFord computes the shortest paths from source to every node reachable
+// from it using the Bellman-Ford algorithm, which unlike ShortestPath allows
+// negative weights. It fails with a *NegativeCycleError if a cycle of negative
+// weight is reachable from source. An undirected edge of negative weight is
+// such a cycle on its own.
+func (s *store) BellmanFord(source string) (*PathTree, error) {
+	return s.BellmanFordContext(context.Background(), source)
+}
+
+// BellmanFordContext is BellmanFord that gives up with the context's error
+// once the context is done, or with ErrVisitLimit once it reaches more nodes
+// than allowed.
+func (s *store) BellmanFordContext(ctx context.Context, source string) (*PathTree, error) {
+	if _, exists := s.nodes.m[source]; !exists {
+		return nil, fmt.Errorf("source node %q not found", source)
+	}
+
+	search := s.newSearch(ctx)
+	if err := search.visit(); err != nil {
+		return nil, err
+	}
+	tree := newPathTree(source)
+	if err := s.bellmanFord(search, tree); err != nil {
+		return nil, err
+	}
+	return tree.clone(), nil
+}
+
+// bellmanFord shortens the paths of tree until they are shortest, relaxing
+// every edge in up to one pass per node. It starts from the distances already
+// in tree, which need not come from a single source.
+func (s *store) bellmanFord(search *search, tree *PathTree) error {
+	edges := sortedEdges(s.edges.m)
+
+	// relax shortens the path to the far end of edge through from, reporting
+	// where it did
+	relax := func(edge *Edge, from string) (string, bool, error) {
+		d, reached := tree.Distances[from]
+		if !reached {
+			return "", false, nil
+		}
+		to := edge.Other(from)
+		alt := d + edge.Weight
+		if old, reached := tree.Distances[to]; reached && !shorter(alt, old, math.Abs(edge.Weight)) {
+			return "", false, nil
+		} else if !reached {
+			if err := search.visit(); err != nil {
+				return "", false, err
+			}
+		}
+		tree.Distances[to] = alt
+		tree.Predecessors[to] = edge
+		return to, true, nil
+	}
+	// pass relaxes every edge once, both ways for undirected edges, and
+	// returns a node it shortened the path to, if any
+	pass := func() (string, bool, error) {
+		var last string
+		changed := false
+		for _, edge := range edges {
+			if err := search.step(); err != nil {
+				return "", false, err
+			}
+			to, ok, err := relax(edge, edge.From)
+			if err != nil {
+				return "", false, err
+			}
+			if ok {
+				last, changed = to, true
+			}
+			if edge.Directed {
+				continue
+			}
+			to, ok, err = relax(edge, edge.To)
+			if err != nil {
+				return "", false, err
+			}
+			if ok {
+				last, changed = to, true
+			}
+		}
+		return last, changed, nil
+	}
+
+	// Shortest paths have fewer edges than there are nodes, so after
+	// len(nodes)-1 passes any path still shortened has a negative cycle
+	for i := 0; i < len(s.nodes.m); i++ {
+		last, changed, err := pass()
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		if i == len(s.nodes.m)-1 {
+			return &NegativeCycleError{Cycle: negativeCycle(tree, last, len(s.nodes.m))}
+		}
+	}
+	return nil
+}
+
+// negativeCycle extracts the negative cycle leading to a node whose path was
+// still being shortened after every path should have been final.
+func negativeCycle(tree *PathTree, id string, n int) *Path {
+	// Following n predecessors is bound to end up on the cycle
+	for i := 0; i < n; i++ {
+		id = tree.Predecessors[id].Other(id)
+	}
+
+	var edges []*Edge
+	nodes := []string{id}
+	for current := id; ; {
+		edge := tree.Predecessors[current]
+		current = edge.Other(current)
+		edges = append(edges, edge)
+		nodes = append(nodes, current)
+		if current == id {
+			break
+		}
+	}
+
+	cycle := &Path{}
+	for i := len(edges) - 1; i >= 0; i-- {
+		cycle.Nodes = append(cycle.Nodes, nodes[i+1])
+		cycle.Edges = append(cycle.Edges, edges[i].clone())
+		cycle.Weight += edges[i].Weight
+	}
+	cycle.Nodes = append(cycle.Nodes, id)
+	return cycle
+}
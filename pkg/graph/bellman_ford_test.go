@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// newArbitrageGraph builds a graph with negative weights but no negative cycle:
+//
+//	A --4--> B --(-3)--> C
+//	A --2--> C --1--> D
+//
+// E is isolated.
+func newArbitrageGraph(t *testing.T) Graph {
+	t.Helper()
+	return buildGraph(t, nodesWithIDs("A", "B", "C", "D", "E"), []*Edge{
+		{ID: "e1", From: "A", To: "B", Weight: 4.0},
+		{ID: "e2", From: "B", To: "C", Weight: -3.0},
+		{ID: "e3", From: "A", To: "C", Weight: 2.0},
+		{ID: "e4", From: "C", To: "D", Weight: 1.0},
+	})
+}
+
+// TestBellmanFord tests distances and predecessors with negative weights
+func TestBellmanFord(t *testing.T) {
+	g := newArbitrageGraph(t)
+
+	tree, err := g.BellmanFord("A")
+	if err != nil {
+		t.Fatalf("BellmanFord failed: %v", err)
+	}
+	want := map[string]float64{"A": 0, "B": 4, "C": 1, "D": 2}
+	if len(tree.Distances) != len(want) {
+		t.Errorf("Expected distances to %d nodes, got %v", len(want), tree.Distances)
+	}
+	for id, d := range want {
+		if got, ok := tree.Distances[id]; !ok || got != d {
+			t.Errorf("Expected distance %f to %s, got %f", d, id, got)
+		}
+	}
+	if _, ok := tree.Distances["E"]; ok {
+		t.Errorf("Expected unreachable node E to have no distance")
+	}
+	if edge := tree.Predecessors["C"]; edge == nil || edge.ID != "e2" {
+		t.Errorf("Expected C to be reached through e2, got %v", edge)
+	}
+	if _, ok := tree.Predecessors["A"]; ok {
+		t.Errorf("Expected the source to have no predecessor")
+	}
+}
+
+// TestBellmanFordNegativeCycle tests that a reachable negative cycle is reported as a path
+func TestBellmanFordNegativeCycle(t *testing.T) {
+	g := newArbitrageGraph(t)
+	g.AddEdge(&Edge{ID: "e5", From: "D", To: "B", Weight: 1.0})
+
+	_, err := g.BellmanFord("A")
+	var cycleErr *NegativeCycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a negative cycle error, got %v", err)
+	}
+	cycle := cycleErr.Cycle
+	if cycle.Weight != -1.0 {
+		t.Errorf("Expected cycle weight -1, got %f", cycle.Weight)
+	}
+	if len(cycle.Nodes) != 4 || cycle.Nodes[0] != cycle.Nodes[3] || len(cycle.Edges) != 3 {
+		t.Fatalf("Expected a closed cycle of 3 edges, got %v", cycle.Nodes)
+	}
+	for i, edge := range cycle.Edges {
+		if edge.From != cycle.Nodes[i] || edge.To != cycle.Nodes[i+1] {
+			t.Errorf("Expected edge %d to lead from %s to %s, got %s -> %s", i, cycle.Nodes[i], cycle.Nodes[i+1], edge.From, edge.To)
+		}
+	}
+
+	// E is isolated, so the cycle is not reachable from it
+	if _, err := g.BellmanFord("E"); err != nil {
+		t.Errorf("Expected no cycle reachable from E, got %v", err)
+	}
+
+	// An undirected negative edge is a cycle on its own
+	u := NewGraph(WithDirectedness(UndirectedGraph))
+	u.AddNode(&Node{ID: "A"})
+	u.AddNode(&Node{ID: "B"})
+	u.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: -1.0})
+	if _, err := u.BellmanFord("A"); !errors.As(err, &cycleErr) {
+		t.Errorf("Expected a negative cycle error for an undirected negative edge, got %v", err)
+	}
+}
+
+// TestNegativeCycleTolerance tests that a cycle of weight zero is not
+// reported as negative because of rounding, while a slightly negative cycle of
+// large weights is
+func TestNegativeCycleTolerance(t *testing.T) {
+	g := buildGraph(t, nodesWithIDs("A", "B", "C"), []*Edge{
+		{ID: "e1", From: "A", To: "B", Weight: 0.1},
+		{ID: "e2", From: "B", To: "C", Weight: 0.7},
+		{ID: "e3", From: "C", To: "A", Weight: -0.8},
+	})
+
+	if _, err := g.BellmanFord("A"); err != nil {
+		t.Errorf("Expected no negative cycle, got %v", err)
+	}
+	for _, alg := range []AllPairsAlgorithm{FloydWarshall, Johnson} {
+		if _, err := g.AllPairsShortestPaths(alg); err != nil {
+			t.Errorf("%v: expected no negative cycle, got %v", alg, err)
+		}
+	}
+
+	// A negative cycle is found however small it is next to its weights
+	g = buildGraph(t, nodesWithIDs("A", "B"), []*Edge{
+		{ID: "e1", From: "A", To: "B", Weight: 1e4},
+		{ID: "e2", From: "B", To: "A", Weight: -1e4 - 1e-6},
+	})
+	var cycleErr *NegativeCycleError
+	if _, err := g.BellmanFord("A"); !errors.As(err, &cycleErr) {
+		t.Errorf("Expected a negative cycle error, got %v", err)
+	}
+	if _, err := g.AllPairsShortestPaths(Johnson); !errors.As(err, &cycleErr) {
+		t.Errorf("Johnson: expected a negative cycle error, got %v", err)
+	}
+}
+
+// TestShortestPathRejectsNegativeWeights tests that Dijkstra refuses negative weights
+func TestShortestPathRejectsNegativeWeights(t *testing.T) {
+	g := newArbitrageGraph(t)
+
+	// Dijkstra would settle C at 2 before finding the path of weight 1
+	if _, _, err := g.ShortestPath("A", "C"); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Expected ErrNegativeWeight, got %v", err)
+	}
+	if _, _, err := g.AStar("A", "C", EuclideanHeuristic("x", "y")); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Expected ErrNegativeWeight from A*, got %v", err)
+	}
+
+	if err := g.SetEdgeWeight("e2", 3.0); err != nil {
+		t.Fatalf("Failed to set edge weight: %v", err)
+	}
+	if _, distance, err := g.ShortestPath("A", "C"); err != nil || distance != 2.0 {
+		t.Errorf("Expected distance 2 once weights are positive, got %f: %v", distance, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.BellmanFordContext(ctx, "A"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected BellmanFord to be cancelled, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+
+	pq "github.com/camwhite18/ArachneGraph/pkg/priority_queue"
+)
+
+// frontier is one half of a bidirectional search: Dijkstra's algorithm run
+// from one end of the path along the edges of adj.
+type frontier struct {
+	adj     cowMap[string, adjacency]
+	dist    map[string]float64
+	prev    map[string]*Edge
+	settled map[string]bool
+	queue   pq.PriorityQueue
+}
+
+func newFrontier(start string, adj cowMap[string, adjacency]) *frontier {
+	f := &frontier{
+		adj:     adj,
+		dist:    map[string]float64{start: 0},
+		prev:    make(map[string]*Edge),
+		settled: make(map[string]bool),
+	}
+	heap.Push(&f.queue, &pq.PriorityQueueItem{NodeID: start, Distance: 0})
+	return f
+}
+
+// top returns a lower bound on the distance of the next node the frontier
+// settles.
+func (f *frontier) top() float64 {
+	if f.queue.Len() == 0 {
+		return math.Inf(1)
+	}
+	return f.queue[0].Distance
+}
+
+// BidirectionalShortestPath finds the shortest path from source to target like
+// ShortestPath, but runs Dijkstra's algorithm forwards from the source and
+// backwards from the target at the same time, stopping once the two searches
+// meet. On large graphs it settles far fewer nodes. Like ShortestPath, it
+// fails with ErrNegativeWeight if any edge has a negative weight.
+func (s *store) BidirectionalShortestPath(source, target string) ([]string, float64, error) {
+	return s.BidirectionalShortestPathContext(context.Background(), source, target)
+}
+
+// BidirectionalShortestPathContext is BidirectionalShortestPath that gives up
+// with the context's error once the context is done, or with ErrVisitLimit
+// once its two searches settle more nodes in total than allowed.
+func (s *store) BidirectionalShortestPathContext(ctx context.Context, source, target string) ([]string, float64, error) {
+	if _, exists := s.nodes.m[source]; !exists {
+		return nil, 0, fmt.Errorf("source node %q not found", source)
+	}
+	if _, exists := s.nodes.m[target]; !exists {
+		return nil, 0, fmt.Errorf("target node %q not found", target)
+	}
+	if s.negative > 0 {
+		return nil, 0, ErrNegativeWeight
+	}
+
+	search := s.newSearch(ctx)
+	forward := newFrontier(source, s.out)
+	backward := newFrontier(target, s.in)
+
+	// best is the weight of the shortest path found so far, through meet
+	best := math.Inf(1)
+	meet := ""
+	if source == target {
+		best, meet = 0, source
+	}
+
+	// Once the two frontiers together cannot beat the best path, it is the
+	// shortest
+	for forward.queue.Len() > 0 && backward.queue.Len() > 0 && forward.top()+backward.top() < best {
+		// Advance the frontier that is closer to its end
+		f, other := forward, backward
+		if backward.top() < forward.top() {
+			f, other = backward, forward
+		}
+
+		item := heap.Pop(&f.queue).(*pq.PriorityQueueItem)
+		current := item.NodeID
+		if f.settled[current] || item.Distance > f.dist[current] {
+			continue
+		}
+		if err := search.visit(); err != nil {
+			return nil, 0, err
+		}
+		f.settled[current] = true
+
+		for _, edge := range s.adjacent(f.adj.m[current]) {
+			neighbor := edge.Other(current)
+			alt := f.dist[current] + edge.Weight
+			if d, reached := f.dist[neighbor]; !reached || alt < d {
+				f.dist[neighbor] = alt
+				f.prev[neighbor] = edge
+				heap.Push(&f.queue, &pq.PriorityQueueItem{NodeID: neighbor, Distance: alt})
+			}
+			if d, reached := other.dist[neighbor]; reached && alt+d < best {
+				best, meet = alt+d, neighbor
+			}
+		}
+	}
+
+	if meet == "" {
+		return nil, 0, fmt.Errorf("no path found from %q to %q", source, target)
+	}
+
+	// Join the forward path to the meeting node with the backward path from
+	// it
+	path := []string{meet}
+	for current := meet; current != source; {
+		current = forward.prev[current].Other(current)
+		path = append(path, current)
+	}
+	reverse(path)
+	for current := meet; current != target; {
+		current = backward.prev[current].Other(current)
+		path = append(path, current)
+	}
+	return path, best, nil
+}
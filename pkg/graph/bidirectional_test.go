@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// TestBidirectionalMatchesShortestPath tests bidirectional search against Dijkstra on every pair
+func TestBidirectionalMatchesShortestPath(t *testing.T) {
+	for _, d := range []Directedness{DirectedGraph, UndirectedGraph} {
+		g := newGrid(t, 5, WithDirectedness(d))
+		var nodes []string
+		for id := range g.AllNodes() {
+			nodes = append(nodes, id)
+		}
+		for _, from := range nodes {
+			for _, to := range nodes {
+				_, want, err := g.ShortestPath(from, to)
+				if err != nil {
+					t.Fatalf("ShortestPath failed: %v", err)
+				}
+				path, got, err := g.BidirectionalShortestPath(from, to)
+				if err != nil {
+					t.Fatalf("BidirectionalShortestPath failed: %v", err)
+				}
+				if math.Abs(got-want) > 1e-9 {
+					t.Fatalf("Expected distance %f from %s to %s, got %f", want, from, to, got)
+				}
+
+				// The path must be walkable and weigh what was reported
+				sum := 0.0
+				for i := 0; i+1 < len(path); i++ {
+					edges, _ := g.EdgesBetween(path[i], path[i+1])
+					if len(edges) == 0 {
+						t.Fatalf("Expected an edge from %s to %s in path %v", path[i], path[i+1], path)
+					}
+					sum += edges[0].Weight
+				}
+				if path[0] != from || path[len(path)-1] != to || math.Abs(sum-got) > 1e-9 {
+					t.Fatalf("Expected a path from %s to %s of weight %f, got %v of weight %f", from, to, got, path, sum)
+				}
+			}
+		}
+	}
+}
+
+// TestBidirectionalNoPath tests missing paths, missing nodes and negative weights
+func TestBidirectionalNoPath(t *testing.T) {
+	g := newDiamond(t)
+
+	if _, _, err := g.BidirectionalShortestPath("D", "A"); err == nil {
+		t.Errorf("Expected error when there is no path")
+	}
+	if _, _, err := g.BidirectionalShortestPath("A", "nowhere"); err == nil {
+		t.Errorf("Expected error for a missing target")
+	}
+	if path, distance, err := g.BidirectionalShortestPath("A", "A"); err != nil || len(path) != 1 || distance != 0 {
+		t.Errorf("Expected an empty path from A to itself, got %v of weight %f: %v", path, distance, err)
+	}
+	if _, _, err := newArbitrageGraph(t).BidirectionalShortestPath("A", "D"); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Expected ErrNegativeWeight, got %v", err)
+	}
+}
+
+func benchmarkShortestPath(b *testing.B, size int, find func(g Graph, source, target string) error) {
+	g := newGrid(b, size)
+	source, target := "0,0", fmt.Sprintf("%d,%d", size/2, size-1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := find(g, source, target); err != nil {
+			b.Fatalf("Failed to find path: %v", err)
+		}
+	}
+}
+
+func BenchmarkShortestPath(b *testing.B) {
+	for _, size := range []int{20, 100} {
+		b.Run(fmt.Sprintf("grid%d", size), func(b *testing.B) {
+			benchmarkShortestPath(b, size, func(g Graph, source, target string) error {
+				_, _, err := g.ShortestPath(source, target)
+				return err
+			})
+		})
+	}
+}
+
+func BenchmarkBidirectionalShortestPath(b *testing.B) {
+	for _, size := range []int{20, 100} {
+		b.Run(fmt.Sprintf("grid%d", size), func(b *testing.B) {
+			benchmarkShortestPath(b, size, func(g Graph, source, target string) error {
+				_, _, err := g.BidirectionalShortestPath(source, target)
+				return err
+			})
+		})
+	}
+}
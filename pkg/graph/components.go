@@ -0,0 +1,354 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Components partitions the nodes of a graph. Members holds the node IDs of
+// each component, sorted by ID, and Component maps every node ID to the index
+// of its component in Members.
+type Components struct {
+	Members   [][]string
+	Component map[string]int
+}
+
+func newComponents(members [][]string) *Components {
+	c := &Components{Members: members, Component: make(map[string]int)}
+	for i, ids := range members {
+		sort.Strings(ids)
+		for _, id := range ids {
+			c.Component[id] = i
+		}
+	}
+	return c
+}
+
+// Same reports whether two nodes are in the same component.
+func (c *Components) Same(a, b string) bool {
+	i, ok := c.Component[a]
+	j, ok2 := c.Component[b]
+	return ok && ok2 && i == j
+}
+
+// StronglyConnectedComponents splits the graph into its strongly connected
+// components, the largest sets of nodes that can each reach all the others,
+// using Tarjan's algorithm. Components come in topological order: every edge
+// between two of them leads from an earlier component to a later one. The
+// endpoints of an undirected edge are always in the same component.
+func (s *store) StronglyConnectedComponents() (*Components, error) {
+	return s.StronglyConnectedComponentsContext(context.Background())
+}
+
+// StronglyConnectedComponentsContext is StronglyConnectedComponents that gives
+// up with the context's error once the context is done, or with ErrVisitLimit
+// once it visits more nodes than allowed.
+func (s *store) StronglyConnectedComponentsContext(ctx context.Context) (*Components, error) {
+	members, err := s.tarjan(s.newSearch(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return newComponents(members), nil
+}
+
+// tarjan returns the strongly connected components in topological order.
+func (s *store) tarjan(search *search) ([][]string, error) {
+	type frame struct {
+		id    string
+		edges []*Edge
+		next  int
+	}
+
+	// index numbers the nodes in the order they are reached, and low is the
+	// lowest index reachable from a node through nodes still on the stack
+	index := make(map[string]int, len(s.nodes.m))
+	low := make(map[string]int, len(s.nodes.m))
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var frames []*frame
+	enter := func(id string) error {
+		if err := search.visit(); err != nil {
+			return err
+		}
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+		frames = append(frames, &frame{id: id, edges: s.adjacent(s.out.m[id])})
+		return nil
+	}
+
+	for _, root := range sortedNodes(s.nodes.m) {
+		if _, reached := index[root.ID]; reached {
+			continue
+		}
+		if err := enter(root.ID); err != nil {
+			return nil, err
+		}
+		for len(frames) > 0 {
+			top := frames[len(frames)-1]
+			if top.next < len(top.edges) {
+				next := top.edges[top.next].Other(top.id)
+				top.next++
+				if _, reached := index[next]; !reached {
+					if err := enter(next); err != nil {
+						return nil, err
+					}
+				} else if onStack[next] {
+					low[top.id] = min(low[top.id], index[next])
+				}
+				continue
+			}
+
+			frames = frames[:len(frames)-1]
+			if len(frames) > 0 {
+				parent := frames[len(frames)-1].id
+				low[parent] = min(low[parent], low[top.id])
+			}
+			// A node that reaches nothing reached before it is the root of a
+			// component made of it and the nodes above it on the stack
+			if low[top.id] == index[top.id] {
+				i := len(stack) - 1
+				for stack[i] != top.id {
+					i--
+				}
+				component := append([]string{}, stack[i:]...)
+				for _, id := range component {
+					onStack[id] = false
+				}
+				stack = stack[:i]
+				components = append(components, component)
+			}
+		}
+	}
+
+	// Tarjan's algorithm finishes a component after every component it
+	// reaches
+	reverse(components)
+	return components, nil
+}
+
+// Condensation returns a new graph with one node for each strongly connected
+// component of this one, which is a directed acyclic graph. Each node takes
+// the lowest ID among its component's members and holds them under the
+// "members" property. The edges between two components are aggregated into
+// one edge between their nodes, with the sum of their weights and their IDs
+// under the "edges" property, and edges within a component are dropped. The
+// new graph has the configuration of this one, but is directed and has no
+// persister.
+func (s *store) Condensation() (Graph, error) {
+	return s.CondensationContext(context.Background())
+}
+
+// CondensationContext is Condensation that gives up with the context's error
+// once the context is done, or with ErrVisitLimit once it visits more nodes
+// than allowed.
+func (s *store) CondensationContext(ctx context.Context) (Graph, error) {
+	components, err := s.StronglyConnectedComponentsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := s.cfg
+	cfg.Directedness = DirectedGraph
+	cfg.Persister = nil
+	c := newStore(cfg)
+	for _, members := range components.Members {
+		node := &Node{ID: members[0], Properties: map[string]any{"members": append([]string{}, members...)}}
+		if err := c.addNode(node); err != nil {
+			return nil, err
+		}
+	}
+
+	type pair struct{ from, to int }
+	aggregated := make(map[pair]*Edge)
+	var order []pair
+	for _, edge := range sortedEdges(s.edges.m) {
+		from, to := components.Component[edge.From], components.Component[edge.To]
+		if from == to {
+			continue
+		}
+		p := pair{from, to}
+		a, exists := aggregated[p]
+		if !exists {
+			fromID, toID := components.Members[from][0], components.Members[to][0]
+			a = &Edge{
+				ID:         fmt.Sprintf("%s->%s", fromID, toID),
+				From:       fromID,
+				To:         toID,
+				Properties: map[string]any{"edges": []string{}},
+			}
+			aggregated[p] = a
+			order = append(order, p)
+		}
+		a.Weight += edge.Weight
+		a.Properties["edges"] = append(a.Properties["edges"].([]string), edge.ID)
+	}
+	for _, p := range order {
+		if err := c.addEdge(aggregated[p]); err != nil {
+			return nil, err
+		}
+	}
+	return &graphImpl{s: c, mu: sync.RWMutex{}}, nil
+}
+
+// WeaklyConnectedComponents splits the graph into its weakly connected
+// components, the largest sets of nodes joined by edges followed in either
+// direction. Components are ordered by their lowest node ID.
+func (s *store) WeaklyConnectedComponents() (*Components, error) {
+	return s.WeaklyConnectedComponentsContext(context.Background())
+}
+
+// WeaklyConnectedComponentsContext is WeaklyConnectedComponents that gives
+// up with the context's error once the context is done, or with
+// ErrVisitLimit once it visits more nodes than allowed.
+func (s *store) WeaklyConnectedComponentsContext(ctx context.Context) (*Components, error) {
+	search := s.newSearch(ctx)
+	seen := make(map[string]bool, len(s.nodes.m))
+	var members [][]string
+	for _, node := range sortedNodes(s.nodes.m) {
+		if seen[node.ID] {
+			continue
+		}
+		component, err := s.weakComponent(search, node.ID, seen)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, component)
+	}
+	return newComponents(members), nil
+}
+
+// SameComponent reports whether two nodes are in the same weakly connected
+// component. With a component index it takes near constant time; without one
+// it searches the component of the first node.
+func (s *store) SameComponent(a, b string) (bool, error) {
+	if _, exists := s.nodes.m[a]; !exists {
+		return false, fmt.Errorf("node %q not found", a)
+	}
+	if _, exists := s.nodes.m[b]; !exists {
+		return false, fmt.Errorf("node %q not found", b)
+	}
+	if s.connected != nil {
+		return s.connected.root(a) == s.connected.root(b), nil
+	}
+
+	seen := make(map[string]bool)
+	if _, err := s.weakComponent(s.newSearch(context.Background()), a, seen); err != nil {
+		return false, err
+	}
+	return seen[b], nil
+}
+
+// ComponentCount returns the number of weakly connected components. With a
+// component index it takes constant time. Having no way to report
+// ErrVisitLimit, it is not bound by WithVisitLimit; ComponentCountContext is.
+func (s *store) ComponentCount() int {
+	count, _ := s.componentCount(s.newSearch(context.Background()).unlimited())
+	return count
+}
+
+// ComponentCountContext is ComponentCount that gives up with the context's
+// error once the context is done, or with ErrVisitLimit once it visits more
+// nodes than allowed. With a component index it does neither.
+func (s *store) ComponentCountContext(ctx context.Context) (int, error) {
+	return s.componentCount(s.newSearch(ctx))
+}
+
+func (s *store) componentCount(search *search) (int, error) {
+	if s.connected != nil {
+		return s.connected.count, nil
+	}
+	seen := make(map[string]bool, len(s.nodes.m))
+	count := 0
+	for id := range s.nodes.m {
+		if !seen[id] {
+			count++
+			if _, err := s.weakComponent(search, id, seen); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return count, nil
+}
+
+// weakComponent returns the nodes reachable from start along edges in either
+// direction, marking them in seen.
+func (s *store) weakComponent(search *search, start string, seen map[string]bool) ([]string, error) {
+	seen[start] = true
+	component := []string{start}
+	for i := 0; i < len(component); i++ {
+		if err := search.visit(); err != nil {
+			return nil, err
+		}
+		current := component[i]
+		for _, adj := range []map[string]*Edge{s.out.m[current].m, s.in.m[current].m} {
+			for _, edge := range adj {
+				if next := edge.Other(current); !seen[next] {
+					seen[next] = true
+					component = append(component, next)
+				}
+			}
+		}
+	}
+	return component, nil
+}
+
+// mutConnected returns a version of the component index that may be written
+// in the store's current epoch.
+func (s *store) mutConnected() *unionFind {
+	if s.connected.epoch != s.epoch {
+		s.connected = s.connected.clone(s.epoch)
+	}
+	return s.connected
+}
+
+// disconnect updates the component index after edges are deleted. A
+// union-find cannot split a set, so unless the endpoints of every deleted
+// edge are still joined by another edge, the index is rebuilt.
+func (s *store) disconnect(edges []*Edge) {
+	if s.connected == nil {
+		return
+	}
+	for _, edge := range edges {
+		if edge.From != edge.To && len(s.edgesBetween(edge.From, edge.To)) == 0 && len(s.edgesBetween(edge.To, edge.From)) == 0 {
+			s.rebuildConnected()
+			return
+		}
+	}
+}
+
+// disconnectNode updates the component index after a node and its edges are
+// deleted. A node without edges to others is alone in its set and is simply
+// removed; otherwise the index is rebuilt.
+func (s *store) disconnectNode(id string, edges []*Edge) {
+	if s.connected == nil {
+		return
+	}
+	for _, edge := range edges {
+		if edge.From != edge.To {
+			s.rebuildConnected()
+			return
+		}
+	}
+	s.mutConnected().remove(id)
+}
+
+func (s *store) rebuildConnected() {
+	u := newUnionFind(s.epoch)
+	for id := range s.nodes.m {
+		u.add(id)
+	}
+	for _, edge := range s.edges.m {
+		u.union(edge.From, edge.To)
+	}
+	// Flatten every tree so that readers reach a root in one step
+	for id := range u.parent {
+		u.find(id)
+	}
+	s.connected = u
+}
@@ -0,0 +1,273 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// newModuleGraph builds a graph of module imports with two cyclic clusters:
+//
+//	a <--> b --> c <--> d --> e
+//
+// f is isolated.
+func newModuleGraph(t *testing.T) Graph {
+	t.Helper()
+	return buildGraph(t, nodesWithIDs("a", "b", "c", "d", "e", "f"), []*Edge{
+		{ID: "ab", From: "a", To: "b", Weight: 1.0},
+		{ID: "ba", From: "b", To: "a", Weight: 1.0},
+		{ID: "bc", From: "b", To: "c", Weight: 2.0},
+		{ID: "ac", From: "a", To: "c", Weight: 3.0},
+		{ID: "cd", From: "c", To: "d", Weight: 1.0},
+		{ID: "dc", From: "d", To: "c", Weight: 1.0},
+		{ID: "de", From: "d", To: "e", Weight: 4.0},
+	})
+}
+
+// TestStronglyConnectedComponents tests splitting a graph into its cyclic clusters
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := newModuleGraph(t)
+
+	components, err := g.StronglyConnectedComponents()
+	if err != nil {
+		t.Fatalf("StronglyConnectedComponents failed: %v", err)
+	}
+	want := [][]string{{"f"}, {"a", "b"}, {"c", "d"}, {"e"}}
+	if !reflect.DeepEqual(components.Members, want) {
+		t.Errorf("Expected components %v, got %v", want, components.Members)
+	}
+	for i, members := range want {
+		for _, id := range members {
+			if components.Component[id] != i {
+				t.Errorf("Expected %s in component %d, got %d", id, i, components.Component[id])
+			}
+		}
+	}
+	if !components.Same("a", "b") || components.Same("b", "c") || components.Same("a", "missing") {
+		t.Errorf("Expected Same to follow the components")
+	}
+
+	// An undirected edge joins its endpoints
+	undirected, err := newTriangle(t, UndirectedGraph).StronglyConnectedComponents()
+	if err != nil {
+		t.Fatalf("StronglyConnectedComponents failed: %v", err)
+	}
+	if len(undirected.Members) != 1 {
+		t.Errorf("Expected one component for an undirected triangle, got %v", undirected.Members)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.StronglyConnectedComponentsContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected StronglyConnectedComponents to be cancelled, got %v", err)
+	}
+}
+
+// TestStronglyConnectedComponentsChain tests a cycle through a long chain of nodes
+func TestStronglyConnectedComponentsChain(t *testing.T) {
+	g := newChain(t, 10000)
+	if err := g.AddEdge(&Edge{ID: "back", From: "n9999", To: "n0", Weight: 1.0}); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+	components, err := g.StronglyConnectedComponents()
+	if err != nil {
+		t.Fatalf("StronglyConnectedComponents failed: %v", err)
+	}
+	if len(components.Members) != 1 || len(components.Members[0]) != 10000 {
+		t.Errorf("Expected a single component of 10000 nodes, got %d components", len(components.Members))
+	}
+}
+
+// TestCondensation tests collapsing each cyclic cluster into a single node
+func TestCondensation(t *testing.T) {
+	g := newModuleGraph(t)
+
+	c, err := g.Condensation()
+	if err != nil {
+		t.Fatalf("Condensation failed: %v", err)
+	}
+	if c.NodeCount() != 4 || c.EdgeCount() != 2 {
+		t.Errorf("Expected 4 nodes and 2 edges, got %d and %d", c.NodeCount(), c.EdgeCount())
+	}
+
+	node, err := c.GetNode("a")
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+	if members := node.Properties["members"]; !reflect.DeepEqual(members, []string{"a", "b"}) {
+		t.Errorf("Expected members [a b], got %v", members)
+	}
+
+	edge, err := c.GetEdge("a", "c")
+	if err != nil {
+		t.Fatalf("Failed to get edge: %v", err)
+	}
+	if edge.Weight != 5 {
+		t.Errorf("Expected aggregated weight 5, got %f", edge.Weight)
+	}
+	if edges := edge.Properties["edges"]; !reflect.DeepEqual(edges, []string{"ac", "bc"}) {
+		t.Errorf("Expected aggregated edges [ac bc], got %v", edges)
+	}
+
+	// The condensation is a DAG that can be sorted
+	order, err := c.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	if want := []string{"a", "f", "c", "e"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+
+	// The original graph is left alone
+	if g.NodeCount() != 6 || g.EdgeCount() != 7 {
+		t.Errorf("Expected the original graph to be unchanged, got %d nodes and %d edges", g.NodeCount(), g.EdgeCount())
+	}
+}
+
+// TestWeaklyConnectedComponents tests splitting a graph into its islands
+func TestWeaklyConnectedComponents(t *testing.T) {
+	g := newModuleGraph(t)
+
+	components, err := g.WeaklyConnectedComponents()
+	if err != nil {
+		t.Fatalf("WeaklyConnectedComponents failed: %v", err)
+	}
+	want := [][]string{{"a", "b", "c", "d", "e"}, {"f"}}
+	if !reflect.DeepEqual(components.Members, want) {
+		t.Errorf("Expected components %v, got %v", want, components.Members)
+	}
+	if g.ComponentCount() != 2 {
+		t.Errorf("Expected 2 components, got %d", g.ComponentCount())
+	}
+
+	// Edges join their endpoints whichever way they point
+	same, err := g.SameComponent("e", "a")
+	if err != nil || !same {
+		t.Errorf("Expected e and a in the same component, got %v: %v", same, err)
+	}
+	same, err = g.SameComponent("a", "f")
+	if err != nil || same {
+		t.Errorf("Expected a and f in different components, got %v: %v", same, err)
+	}
+	if _, err := g.SameComponent("a", "missing"); err == nil {
+		t.Errorf("Expected error for a missing node")
+	}
+
+	// ComponentCount cannot report the limit, so it is not bound by it
+	chain := newChain(t, 10, WithVisitLimit(5))
+	if chain.ComponentCount() != 1 {
+		t.Errorf("Expected 1 component, got %d", chain.ComponentCount())
+	}
+	if _, err := chain.ComponentCountContext(context.Background()); !errors.Is(err, ErrVisitLimit) {
+		t.Errorf("Expected ComponentCountContext to hit the visit limit, got %v", err)
+	}
+}
+
+// TestComponentIndex tests that the index follows added and deleted nodes and edges
+func TestComponentIndex(t *testing.T) {
+	g := newChain(t, 4, WithComponentIndex())
+	sameComponent := func(a, b string) bool {
+		t.Helper()
+		same, err := g.SameComponent(a, b)
+		if err != nil {
+			t.Fatalf("SameComponent failed: %v", err)
+		}
+		return same
+	}
+
+	if g.ComponentCount() != 1 || !sameComponent("n0", "n3") {
+		t.Errorf("Expected the chain to be one component")
+	}
+
+	// Deleting one of two parallel edges keeps the chain together
+	if err := g.AddEdge(&Edge{ID: "e2b", From: "n2", To: "n1", Weight: 1.0}); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+	if err := g.DeleteEdgeByID("e2"); err != nil {
+		t.Fatalf("Failed to delete edge: %v", err)
+	}
+	if g.ComponentCount() != 1 || !sameComponent("n0", "n3") {
+		t.Errorf("Expected the chain to stay one component")
+	}
+
+	snapshot := g.Snapshot()
+	if err := g.DeleteEdge("n2", "n1"); err != nil {
+		t.Fatalf("Failed to delete edge: %v", err)
+	}
+	if g.ComponentCount() != 2 || sameComponent("n1", "n2") || !sameComponent("n2", "n3") {
+		t.Errorf("Expected deleting the last edge between n1 and n2 to split the chain")
+	}
+	if same, _ := snapshot.SameComponent("n1", "n2"); !same || snapshot.ComponentCount() != 1 {
+		t.Errorf("Expected the snapshot to keep the chain whole")
+	}
+
+	tx := g.Begin()
+	if err := tx.AddEdge(&Edge{ID: "join", From: "n0", To: "n3", Weight: 1.0}); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+	if err := tx.DeleteNode("n1"); err != nil {
+		t.Fatalf("Failed to delete node: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if g.ComponentCount() != 2 || sameComponent("n0", "n3") || !sameComponent("n0", "n1") {
+		t.Errorf("Expected rolling back to restore the components")
+	}
+
+	if err := g.AddNode(&Node{ID: "lone"}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	if g.ComponentCount() != 3 {
+		t.Errorf("Expected a new node to be a component of its own, got %d", g.ComponentCount())
+	}
+	if err := g.DeleteNode("lone"); err != nil {
+		t.Fatalf("Failed to delete node: %v", err)
+	}
+	if g.ComponentCount() != 2 {
+		t.Errorf("Expected 2 components, got %d", g.ComponentCount())
+	}
+}
+
+// TestComponentIndexRandom tests the index against a search after random changes
+func TestComponentIndexRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	g := NewGraph(WithComponentIndex())
+	for i := 0; i < 50; i++ {
+		if err := g.AddNode(&Node{ID: fmt.Sprintf("n%d", i)}); err != nil {
+			t.Fatalf("Failed to add node: %v", err)
+		}
+	}
+
+	var edges []string
+	for i := 0; i < 500; i++ {
+		if len(edges) > 0 && r.Intn(3) == 0 {
+			j := r.Intn(len(edges))
+			if err := g.DeleteEdgeByID(edges[j]); err != nil {
+				t.Fatalf("Failed to delete edge: %v", err)
+			}
+			edges = append(edges[:j], edges[j+1:]...)
+		} else {
+			edge := &Edge{ID: fmt.Sprintf("e%d", i), From: fmt.Sprintf("n%d", r.Intn(50)), To: fmt.Sprintf("n%d", r.Intn(50)), Weight: 1.0}
+			if err := g.AddEdge(edge); err != nil {
+				t.Fatalf("Failed to add edge: %v", err)
+			}
+			edges = append(edges, edge.ID)
+		}
+
+		components, err := g.WeaklyConnectedComponents()
+		if err != nil {
+			t.Fatalf("WeaklyConnectedComponents failed: %v", err)
+		}
+		if g.ComponentCount() != len(components.Members) {
+			t.Fatalf("Expected %d components after step %d, got %d", len(components.Members), i, g.ComponentCount())
+		}
+		a, b := fmt.Sprintf("n%d", r.Intn(50)), fmt.Sprintf("n%d", r.Intn(50))
+		if same, _ := g.SameComponent(a, b); same != components.Same(a, b) {
+			t.Fatalf("Expected SameComponent(%s, %s) to be %v after step %d", a, b, components.Same(a, b), i)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package graph
+
+import "fmt"
+
+// Directedness selects how a graph treats the direction of its edges.
+type Directedness int
+
+const (
+	// DirectedGraph makes every edge directed, from From to To.
+	DirectedGraph Directedness = iota
+	// UndirectedGraph makes every edge traversable in both directions.
+	UndirectedGraph
+	// MixedGraph lets each edge choose through its Directed field.
+	MixedGraph
+)
+
+func (d Directedness) String() string {
+	switch d {
+	case DirectedGraph:
+		return "directed"
+	case UndirectedGraph:
+		return "undirected"
+	case MixedGraph:
+		return "mixed"
+	default:
+		return fmt.Sprintf("Directedness(%d)", int(d))
+	}
+}
+
+func (d Directedness) MarshalText() ([]byte, error) {
+	if d < DirectedGraph || d > MixedGraph {
+		return nil, fmt.Errorf("unknown directedness %d", int(d))
+	}
+	return []byte(d.String()), nil
+}
+
+func (d *Directedness) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "directed":
+		*d = DirectedGraph
+	case "undirected":
+		*d = UndirectedGraph
+	case "mixed":
+		*d = MixedGraph
+	default:
+		return fmt.Errorf("unknown directedness %q", text)
+	}
+	return nil
+}
+
+// Ordering selects the order in which a node's edges are returned by
+// Neighbors and Incoming and followed by traversals and path searches.
+type Ordering int
+
+const (
+	// OrderByID orders edges by the ID of the node at their other end, and
+	// parallel edges by their own ID.
+	OrderByID Ordering = iota
+	// OrderByInsertion orders edges by when they were added to the graph.
+	OrderByInsertion
+	// OrderByComparator orders edges with the comparator set by
+	// WithEdgeComparator, breaking ties by edge ID.
+	OrderByComparator
+	// Unordered skips sorting, leaving the order unspecified and varying
+	// from call to call. It is the fastest choice when order does not matter.
+	Unordered
+)
+
+func (o Ordering) String() string {
+	switch o {
+	case OrderByID:
+		return "id"
+	case OrderByInsertion:
+		return "insertion"
+	case OrderByComparator:
+		return "comparator"
+	case Unordered:
+		return "unordered"
+	default:
+		return fmt.Sprintf("Ordering(%d)", int(o))
+	}
+}
+
+func (o Ordering) MarshalText() ([]byte, error) {
+	if o < OrderByID || o > Unordered {
+		return nil, fmt.Errorf("unknown ordering %d", int(o))
+	}
+	return []byte(o.String()), nil
+}
+
+func (o *Ordering) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "id":
+		*o = OrderByID
+	case "insertion":
+		*o = OrderByInsertion
+	case "comparator":
+		*o = OrderByComparator
+	case "unordered":
+		*o = Unordered
+	default:
+		return fmt.Errorf("unknown ordering %q", text)
+	}
+	return nil
+}
+
+// Config describes the behavior of a graph. It is set through the options
+// passed to NewGraph and cannot change afterwards.
+type Config struct {
+	Directedness Directedness
+	// Multigraph allows several edges between the same pair of nodes.
+	Multigraph bool
+	// SelfLoops allows edges from a node to itself.
+	SelfLoops bool
+	Ordering  Ordering
+	// EdgeComparator orders edges when Ordering is OrderByComparator.
+	EdgeComparator func(a, b *Edge) int `json:"-"`
+	// VisitLimit caps the nodes a single run of an algorithm may visit.
+	// Zero means no limit.
+	VisitLimit int
+	// ComponentIndex keeps the weakly connected components up to date as
+	// the graph changes, so that SameComponent and ComponentCount need no
+	// search.
+	ComponentIndex bool
+	// IDGenerator, when set, supplies the ID of nodes and edges added
+	// without one.
+	IDGenerator func() string `json:"-"`
+	// Persister is used by Graph.Save.
+	Persister Persister `json:"-"`
+}
+
+// defaultConfig returns the configuration of a graph created without options:
+// a directed multigraph that allows self-loops and orders edges by ID.
+func defaultConfig() Config {
+	return Config{
+		Directedness: DirectedGraph,
+		Multigraph:   true,
+		SelfLoops:    true,
+	}
+}
+
+// validate rejects settings that cannot be used together.
+func (c Config) validate() error {
+	if _, err := c.Directedness.MarshalText(); err != nil {
+		return err
+	}
+	// Whether a directed and an undirected edge between the same nodes are
+	// parallel is ambiguous, so mixed graphs must allow both.
+	if c.Directedness == MixedGraph && !c.Multigraph {
+		return fmt.Errorf("a mixed graph must be a multigraph")
+	}
+	if _, err := c.Ordering.MarshalText(); err != nil {
+		return err
+	}
+	if c.Ordering == OrderByComparator && c.EdgeComparator == nil {
+		return fmt.Errorf("ordering by comparator requires an edge comparator")
+	}
+	if c.VisitLimit < 0 {
+		return fmt.Errorf("visit limit %d is negative", c.VisitLimit)
+	}
+	return nil
+}
+
+// Option configures a graph created by New or NewGraph.
+type Option func(*Config)
+
+// WithDirectedness sets whether the graph's edges are directed, undirected,
+// or chosen per edge. Graphs are directed by default.
+func WithDirectedness(d Directedness) Option {
+	return func(c *Config) {
+		c.Directedness = d
+	}
+}
+
+// WithMultigraph sets whether several edges may join the same pair of nodes.
+// When disabled, adding a second edge from one node to another fails; in an
+// undirected graph this includes an edge joining them the other way around.
+// Graphs are multigraphs by default.
+func WithMultigraph(allow bool) Option {
+	return func(c *Config) {
+		c.Multigraph = allow
+	}
+}
+
+// WithSelfLoops sets whether an edge may lead from a node to itself. Self-loops
+// are allowed by default.
+func WithSelfLoops(allow bool) Option {
+	return func(c *Config) {
+		c.SelfLoops = allow
+	}
+}
+
+// WithOrdering sets the order of a node's edges. Edges are ordered by ID by
+// default.
+func WithOrdering(o Ordering) Option {
+	return func(c *Config) {
+		c.Ordering = o
+	}
+}
+
+// WithEdgeComparator orders a node's edges with cmp, which returns a negative
+// number when a comes before b, a positive number when it comes after, and
+// zero when their order does not matter. The edges passed to cmp are shared
+// with the graph and must not be modified.
+func WithEdgeComparator(cmp func(a, b *Edge) int) Option {
+	return func(c *Config) {
+		c.Ordering = OrderByComparator
+		c.EdgeComparator = cmp
+	}
+}
+
+// WithVisitLimit makes traversals and path searches fail with ErrVisitLimit
+// instead of visiting more than limit nodes, bounding the work a single query
+// can do. There is no limit by default.
+func WithVisitLimit(limit int) Option {
+	return func(c *Config) {
+		c.VisitLimit = limit
+	}
+}
+
+// WithComponentIndex makes the graph maintain a union-find index of its
+// weakly connected components. Adding nodes and edges updates the index in
+// near constant time; deleting an edge that was the last one between its
+// endpoints, or a node with edges, rebuilds it in linear time.
+func WithComponentIndex() Option {
+	return func(c *Config) {
+		c.ComponentIndex = true
+	}
+}
+
+// WithIDGenerator makes the graph call gen for the ID of every node or edge
+// added with an empty ID, and write the generated ID back to the caller's
+// node or edge. gen is called with the graph's write lock held. Without a
+// generator, edges must be given an ID.
+func WithIDGenerator(gen func() string) Option {
+	return func(c *Config) {
+		c.IDGenerator = gen
+	}
+}
+
+// WithPersister sets the persister Graph.Save writes through.
+func WithPersister(p Persister) Option {
+	return func(c *Config) {
+		c.Persister = p
+	}
+}
+
+// withConfig replaces the whole configuration, so that later options apply on
+// top of it.
+func withConfig(cfg Config) Option {
+	return func(c *Config) {
+		*c = cfg
+	}
+}
@@ -0,0 +1,258 @@
+package graph
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// newTriangle builds A-B-C with a long A-C edge in a graph of the given mode
+func newTriangle(t *testing.T, d Directedness) Graph {
+	t.Helper()
+	return buildGraph(t, nodesWithIDs("A", "B", "C"), []*Edge{
+		{ID: "e1", From: "A", To: "B", Weight: 1.0},
+		{ID: "e2", From: "B", To: "C", Weight: 2.0},
+		{ID: "e3", From: "A", To: "C", Weight: 4.0},
+	}, WithDirectedness(d))
+}
+
+// TestUndirectedTraversal tests that undirected edges are followed both ways
+func TestUndirectedTraversal(t *testing.T) {
+	g := newTriangle(t, UndirectedGraph)
+
+	neighbors, err := g.Neighbors("C")
+	if err != nil {
+		t.Fatalf("Failed to get neighbors: %v", err)
+	}
+	var others []string
+	for _, edge := range neighbors {
+		if edge.Directed {
+			t.Errorf("Expected edge %s to be undirected", edge.ID)
+		}
+		others = append(others, edge.Other("C"))
+	}
+	sort.Strings(others)
+	if !equalIDs(others, []string{"A", "B"}) {
+		t.Errorf("Expected neighbors [A B], got %v", others)
+	}
+
+	dfs, err := g.DFS("C")
+	if err != nil {
+		t.Fatalf("DFS failed: %v", err)
+	}
+	if len(dfs) != 3 {
+		t.Errorf("Expected DFS to visit 3 nodes, got %v", dfs)
+	}
+	bfs, err := g.BFS("C")
+	if err != nil {
+		t.Fatalf("BFS failed: %v", err)
+	}
+	if len(bfs) != 3 {
+		t.Errorf("Expected BFS to visit 3 nodes, got %v", bfs)
+	}
+
+	path, distance, err := g.ShortestPath("C", "A")
+	if err != nil {
+		t.Fatalf("ShortestPath failed: %v", err)
+	}
+	if !equalIDs(path, []string{"C", "B", "A"}) || distance != 3.0 {
+		t.Errorf("Expected path [C B A] with distance 3, got %v with %f", path, distance)
+	}
+}
+
+// TestUndirectedDeleteEdge tests that an undirected edge can be deleted from either end
+func TestUndirectedDeleteEdge(t *testing.T) {
+	g := newTriangle(t, UndirectedGraph)
+
+	if _, err := g.GetEdge("B", "A"); err != nil {
+		t.Fatalf("Failed to get edge from its To end: %v", err)
+	}
+	if err := g.DeleteEdge("B", "A"); err != nil {
+		t.Fatalf("Failed to delete edge: %v", err)
+	}
+	if _, err := g.GetEdge("A", "B"); err == nil {
+		t.Errorf("Expected edge A-B to be deleted")
+	}
+	if neighbors, _ := g.Neighbors("B"); len(neighbors) != 1 {
+		t.Errorf("Expected B to keep 1 edge, got %d", len(neighbors))
+	}
+
+	if err := g.DeleteNode("C"); err != nil {
+		t.Fatalf("Failed to delete node: %v", err)
+	}
+	if g.EdgeCount() != 0 {
+		t.Errorf("Expected no edges left, got %d", g.EdgeCount())
+	}
+	for _, id := range []string{"A", "B"} {
+		if neighbors, _ := g.Neighbors(id); len(neighbors) != 0 {
+			t.Errorf("Expected %s to have no edges, got %d", id, len(neighbors))
+		}
+		if incoming, _ := g.Incoming(id); len(incoming) != 0 {
+			t.Errorf("Expected %s to have no incoming edges, got %d", id, len(incoming))
+		}
+	}
+}
+
+// TestMixedGraph tests that a mixed graph honors each edge's Directed field
+func TestMixedGraph(t *testing.T) {
+	g := NewGraph(WithDirectedness(MixedGraph))
+	for _, id := range []string{"A", "B", "C"} {
+		g.AddNode(&Node{ID: id})
+	}
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Directed: true, Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e2", From: "B", To: "C", Weight: 1.0})
+
+	path, _, err := g.ShortestPath("C", "B")
+	if err != nil {
+		t.Fatalf("Expected the undirected edge to lead from C to B: %v", err)
+	}
+	if !equalIDs(path, []string{"C", "B"}) {
+		t.Errorf("Expected path [C B], got %v", path)
+	}
+	if _, _, err := g.ShortestPath("C", "A"); err == nil {
+		t.Errorf("Expected the directed edge not to lead from B to A")
+	}
+
+	// Directed graphs ignore the field and undirected graphs override it
+	directed := NewGraph()
+	directed.AddNode(&Node{ID: "A"})
+	directed.AddNode(&Node{ID: "B"})
+	directed.AddEdge(&Edge{ID: "e1", From: "A", To: "B"})
+	if edge, _ := directed.GetEdgeByID("e1"); !edge.Directed {
+		t.Errorf("Expected edges of a directed graph to be directed")
+	}
+	if _, err := directed.GetEdge("B", "A"); err == nil {
+		t.Errorf("Expected directed edge not to be found from its To end")
+	}
+}
+
+// TestBoltPersistUndirected tests that the graph mode survives a save and load
+func TestBoltPersistUndirected(t *testing.T) {
+	persister := &BoltPersist{Path: filepath.Join(t.TempDir(), "graph.db")}
+	g := newTriangle(t, UndirectedGraph)
+
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	loaded, err := persister.Load()
+	if err != nil {
+		t.Fatalf("Failed to load graph: %v", err)
+	}
+	if loaded.Config().Directedness != UndirectedGraph {
+		t.Errorf("Expected an undirected graph, got %v", loaded.Config().Directedness)
+	}
+	if loaded.EdgeCount() != 3 {
+		t.Errorf("Expected 3 edges, got %d", loaded.EdgeCount())
+	}
+	if _, err := loaded.GetEdge("C", "B"); err != nil {
+		t.Errorf("Failed to get undirected edge from its To end: %v", err)
+	}
+}
+
+// TestSimpleGraphOptions tests that parallel edges and self-loops can be disallowed
+func TestSimpleGraphOptions(t *testing.T) {
+	g := NewGraph(WithDirectedness(UndirectedGraph), WithMultigraph(false), WithSelfLoops(false))
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+
+	if err := g.AddEdge(&Edge{ID: "e1", From: "A", To: "B"}); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+	if err := g.AddEdge(&Edge{ID: "e2", From: "A", To: "B"}); err == nil {
+		t.Errorf("Expected error when adding a parallel edge")
+	}
+	if err := g.AddEdge(&Edge{ID: "e3", From: "B", To: "A"}); err == nil {
+		t.Errorf("Expected error when adding a reversed undirected edge")
+	}
+	if err := g.AddEdge(&Edge{ID: "e4", From: "A", To: "A"}); err == nil {
+		t.Errorf("Expected error when adding a self-loop")
+	}
+
+	// A directed simple graph still allows an edge each way
+	d := NewGraph(WithMultigraph(false))
+	d.AddNode(&Node{ID: "A"})
+	d.AddNode(&Node{ID: "B"})
+	d.AddEdge(&Edge{ID: "e1", From: "A", To: "B"})
+	if err := d.AddEdge(&Edge{ID: "e2", From: "B", To: "A"}); err != nil {
+		t.Errorf("Failed to add the reverse of a directed edge: %v", err)
+	}
+}
+
+// TestInvalidConfig tests that incompatible options are rejected
+func TestInvalidConfig(t *testing.T) {
+	if _, err := New(WithDirectedness(MixedGraph), WithMultigraph(false)); err == nil {
+		t.Errorf("Expected error for a mixed simple graph")
+	}
+	if _, err := New(WithDirectedness(Directedness(7))); err == nil {
+		t.Errorf("Expected error for an unknown directedness")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected NewGraph to panic on an invalid config")
+		}
+	}()
+	NewGraph(WithDirectedness(MixedGraph), WithMultigraph(false))
+}
+
+// TestIDGenerator tests that nodes and edges added without an ID are given one
+func TestIDGenerator(t *testing.T) {
+	next := 0
+	g := NewGraph(WithIDGenerator(func() string {
+		next++
+		return fmt.Sprintf("id%d", next)
+	}))
+
+	a, b := &Node{}, &Node{}
+	if err := g.AddNode(a); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	if err := g.AddNode(b); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	edge := &Edge{From: a.ID, To: b.ID}
+	if err := g.AddEdge(edge); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+	if a.ID != "id1" || b.ID != "id2" || edge.ID != "id3" {
+		t.Errorf("Expected IDs id1, id2 and id3, got %q, %q and %q", a.ID, b.ID, edge.ID)
+	}
+	if _, err := g.GetEdgeByID("id3"); err != nil {
+		t.Errorf("Failed to get edge by generated ID: %v", err)
+	}
+
+	// A transaction rolls back generated IDs like any other
+	tx := g.Begin()
+	tx.AddNode(&Node{})
+	tx.Rollback()
+	if _, err := g.GetNode("id4"); err == nil {
+		t.Errorf("Expected rolled back node to be removed")
+	}
+}
+
+// TestGraphSave tests saving through a configured persister and loading with options
+func TestGraphSave(t *testing.T) {
+	if err := NewGraph().Save(); err == nil {
+		t.Errorf("Expected error when saving without a persister")
+	}
+
+	persister := &BoltPersist{Path: filepath.Join(t.TempDir(), "graph.db")}
+	g := NewGraph(WithPersister(persister), WithMultigraph(false))
+	g.AddNode(&Node{ID: "A"})
+	if err := g.Save(); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	loaded, err := persister.Load(WithSelfLoops(false))
+	if err != nil {
+		t.Fatalf("Failed to load graph: %v", err)
+	}
+	cfg := loaded.Config()
+	if cfg.Multigraph || cfg.SelfLoops {
+		t.Errorf("Expected saved and loading options to apply, got %+v", cfg)
+	}
+	if cfg.Persister != nil {
+		t.Errorf("Expected the persister not to be saved")
+	}
+}
@@ -0,0 +1,397 @@
+package graph
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// ConstraintKind is the rule a constraint enforces.
+type ConstraintKind int
+
+const (
+	// UniqueConstraint forbids two elements from holding the same value for
+	// the property. Elements without the property are not affected.
+	UniqueConstraint ConstraintKind = iota
+	// ExistsConstraint requires every element to hold the property.
+	ExistsConstraint
+	// TypeConstraint requires the property, when present, to be of a given
+	// PropertyType.
+	TypeConstraint
+)
+
+func (k ConstraintKind) String() string {
+	switch k {
+	case UniqueConstraint:
+		return "unique"
+	case ExistsConstraint:
+		return "exists"
+	case TypeConstraint:
+		return "type"
+	default:
+		return fmt.Sprintf("ConstraintKind(%d)", int(k))
+	}
+}
+
+func (k ConstraintKind) MarshalText() ([]byte, error) {
+	if k < UniqueConstraint || k > TypeConstraint {
+		return nil, fmt.Errorf("unknown constraint kind %d", int(k))
+	}
+	return []byte(k.String()), nil
+}
+
+func (k *ConstraintKind) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "unique":
+		*k = UniqueConstraint
+	case "exists":
+		*k = ExistsConstraint
+	case "type":
+		*k = TypeConstraint
+	default:
+		return fmt.Errorf("unknown constraint kind %q", text)
+	}
+	return nil
+}
+
+// ConstraintTarget selects whether a constraint applies to nodes or edges.
+type ConstraintTarget int
+
+const (
+	NodeTarget ConstraintTarget = iota
+	EdgeTarget
+)
+
+func (t ConstraintTarget) String() string {
+	switch t {
+	case NodeTarget:
+		return "node"
+	case EdgeTarget:
+		return "edge"
+	default:
+		return fmt.Sprintf("ConstraintTarget(%d)", int(t))
+	}
+}
+
+func (t ConstraintTarget) MarshalText() ([]byte, error) {
+	if t != NodeTarget && t != EdgeTarget {
+		return nil, fmt.Errorf("unknown constraint target %d", int(t))
+	}
+	return []byte(t.String()), nil
+}
+
+func (t *ConstraintTarget) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "node":
+		*t = NodeTarget
+	case "edge":
+		*t = EdgeTarget
+	default:
+		return fmt.Errorf("unknown constraint target %q", text)
+	}
+	return nil
+}
+
+// PropertyType is the value type required by a TypeConstraint. Numbers of any
+// Go numeric type satisfy NumberProperty.
+type PropertyType int
+
+const (
+	StringProperty PropertyType = iota
+	NumberProperty
+	BoolProperty
+)
+
+func (p PropertyType) String() string {
+	switch p {
+	case StringProperty:
+		return "string"
+	case NumberProperty:
+		return "number"
+	case BoolProperty:
+		return "bool"
+	default:
+		return fmt.Sprintf("PropertyType(%d)", int(p))
+	}
+}
+
+func (p PropertyType) MarshalText() ([]byte, error) {
+	if p < StringProperty || p > BoolProperty {
+		return nil, fmt.Errorf("unknown property type %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+func (p *PropertyType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "string":
+		*p = StringProperty
+	case "number":
+		*p = NumberProperty
+	case "bool":
+		*p = BoolProperty
+	default:
+		return fmt.Errorf("unknown property type %q", text)
+	}
+	return nil
+}
+
+func (p PropertyType) valueKind() valueKind {
+	switch p {
+	case NumberProperty:
+		return numberValue
+	case BoolProperty:
+		return boolValue
+	default:
+		return stringValue
+	}
+}
+
+// Constraint is a schema rule on a property of nodes with a label, or of
+// edges with a type when Target is EdgeTarget. An empty Label applies the
+// constraint to every node or edge. Type is only used by TypeConstraint.
+type Constraint struct {
+	Kind     ConstraintKind
+	Target   ConstraintTarget
+	Label    string
+	Property string
+	Type     PropertyType `json:",omitempty"`
+}
+
+func (c Constraint) String() string {
+	s := fmt.Sprintf("%s %s constraint on %s.%s", c.Target, c.Kind, c.Label, c.Property)
+	if c.Kind == TypeConstraint {
+		s += " (" + c.Type.String() + ")"
+	}
+	return s
+}
+
+// ConstraintViolationError is returned when a mutation would break a
+// constraint. ConflictingID is set for unique constraints and holds the ID of
+// the element already holding Value.
+type ConstraintViolationError struct {
+	Constraint    Constraint
+	ElementID     string
+	Value         any
+	ConflictingID string
+}
+
+func (e *ConstraintViolationError) Error() string {
+	element := fmt.Sprintf("%s %q", e.Constraint.Target, e.ElementID)
+	switch e.Constraint.Kind {
+	case UniqueConstraint:
+		if e.ConflictingID == "" {
+			return fmt.Sprintf("%s violates %s: value %v is not comparable", element, e.Constraint, e.Value)
+		}
+		return fmt.Sprintf("%s violates %s: value %v is already held by %s %q",
+			element, e.Constraint, e.Value, e.Constraint.Target, e.ConflictingID)
+	case ExistsConstraint:
+		return fmt.Sprintf("%s violates %s: property is missing", element, e.Constraint)
+	default:
+		return fmt.Sprintf("%s violates %s: value %v has type %T", element, e.Constraint, e.Value, e.Value)
+	}
+}
+
+// constraintState is an active constraint. Unique constraints track the owner
+// of each value so that violations are detected without a scan.
+type constraintState struct {
+	def    Constraint
+	owners map[indexValue]string
+	epoch  uint64
+}
+
+func (c *constraintState) clone(epoch uint64) *constraintState {
+	return &constraintState{def: c.def, owners: maps.Clone(c.owners), epoch: epoch}
+}
+
+// applies reports whether the constraint covers an element of the given
+// target whose label, or type for edges, matches the constraint's.
+func (c *constraintState) applies(target ConstraintTarget, matchesLabel bool) bool {
+	return c.def.Target == target && (c.def.Label == "" || matchesLabel)
+}
+
+// check returns a violation if the element breaks the constraint.
+func (c *constraintState) check(id string, properties map[string]any) error {
+	value, exists := properties[c.def.Property]
+	switch c.def.Kind {
+	case UniqueConstraint:
+		if !exists {
+			return nil
+		}
+		v, ok := normalizeValue(value)
+		if !ok {
+			return &ConstraintViolationError{Constraint: c.def, ElementID: id, Value: value}
+		}
+		if owner, held := c.owners[v]; held && owner != id {
+			return &ConstraintViolationError{Constraint: c.def, ElementID: id, Value: value, ConflictingID: owner}
+		}
+	case ExistsConstraint:
+		if !exists {
+			return &ConstraintViolationError{Constraint: c.def, ElementID: id}
+		}
+	case TypeConstraint:
+		if !exists {
+			return nil
+		}
+		if v, ok := normalizeValue(value); !ok || v.kind != c.def.Type.valueKind() {
+			return &ConstraintViolationError{Constraint: c.def, ElementID: id, Value: value}
+		}
+	}
+	return nil
+}
+
+func (c *constraintState) add(id string, properties map[string]any) {
+	if c.def.Kind != UniqueConstraint {
+		return
+	}
+	if v, ok := normalizeValue(properties[c.def.Property]); ok {
+		c.owners[v] = id
+	}
+}
+
+func (c *constraintState) remove(id string, properties map[string]any) {
+	if c.def.Kind != UniqueConstraint {
+		return
+	}
+	if v, ok := normalizeValue(properties[c.def.Property]); ok && c.owners[v] == id {
+		delete(c.owners, v)
+	}
+}
+
+// createConstraint declares a constraint and enforces it on every later
+// mutation. It fails with a *ConstraintViolationError if existing data already
+// breaks the constraint.
+func (s *store) createConstraint(c Constraint) error {
+	if c.Property == "" {
+		return fmt.Errorf("constraint property must not be empty")
+	}
+	if _, err := c.Kind.MarshalText(); err != nil {
+		return err
+	}
+	if _, err := c.Target.MarshalText(); err != nil {
+		return err
+	}
+	if c.Kind != TypeConstraint {
+		c.Type = 0
+	} else if _, err := c.Type.MarshalText(); err != nil {
+		return err
+	}
+	if _, exists := s.constraints.m[c]; exists {
+		return fmt.Errorf("%s already exists", c)
+	}
+
+	state := &constraintState{def: c, epoch: s.epoch}
+	if c.Kind == UniqueConstraint {
+		state.owners = make(map[indexValue]string)
+	}
+	if c.Target == NodeTarget {
+		for _, node := range sortedNodes(s.nodes.m) {
+			if !state.applies(NodeTarget, node.HasLabel(c.Label)) {
+				continue
+			}
+			if err := state.check(node.ID, node.Properties); err != nil {
+				return err
+			}
+			state.add(node.ID, node.Properties)
+		}
+	} else {
+		for _, edge := range sortedEdges(s.edges.m) {
+			if !state.applies(EdgeTarget, edge.Type == c.Label) {
+				continue
+			}
+			if err := state.check(edge.ID, edge.Properties); err != nil {
+				return err
+			}
+			state.add(edge.ID, edge.Properties)
+		}
+	}
+	s.constraints.mut(s.epoch)[c] = state
+	i, _ := slices.BinarySearchFunc(s.constraintOrder, c, compareConstraints)
+	s.constraintOrder = slices.Insert(slices.Clone(s.constraintOrder), i, c)
+	return nil
+}
+
+func (s *store) dropConstraint(c Constraint) error {
+	if c.Kind != TypeConstraint {
+		c.Type = 0
+	}
+	if _, exists := s.constraints.m[c]; !exists {
+		return fmt.Errorf("%s not found", c)
+	}
+	delete(s.constraints.mut(s.epoch), c)
+	s.constraintOrder = slices.DeleteFunc(slices.Clone(s.constraintOrder), func(other Constraint) bool { return other == c })
+	return nil
+}
+
+// Constraints returns every declared constraint, sorted by target, label,
+// property and kind.
+func (s *store) Constraints() []Constraint {
+	return slices.Clone(s.constraintOrder)
+}
+
+func compareConstraints(a, b Constraint) int {
+	return cmp.Or(
+		cmp.Compare(a.Target, b.Target),
+		cmp.Compare(a.Label, b.Label),
+		cmp.Compare(a.Property, b.Property),
+		cmp.Compare(a.Kind, b.Kind),
+		cmp.Compare(a.Type, b.Type),
+	)
+}
+
+// checkNode returns the first constraint, in the order of Constraints, that
+// the node would violate if stored.
+func (s *store) checkNode(node *Node) error {
+	for _, def := range s.constraintOrder {
+		if c := s.constraints.m[def]; c.applies(NodeTarget, node.HasLabel(c.def.Label)) {
+			if err := c.check(node.ID, node.Properties); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkEdge returns the first constraint, in the order of Constraints, that
+// the edge would violate if stored.
+func (s *store) checkEdge(edge *Edge) error {
+	for _, def := range s.constraintOrder {
+		if c := s.constraints.m[def]; c.applies(EdgeTarget, edge.Type == c.def.Label) {
+			if err := c.check(edge.ID, edge.Properties); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mutConstraint returns a version of an existing constraint that may be
+// written in the store's current epoch.
+func (s *store) mutConstraint(key Constraint) *constraintState {
+	c := s.constraints.m[key]
+	if c.epoch != s.epoch {
+		c = c.clone(s.epoch)
+		s.constraints.mut(s.epoch)[key] = c
+	}
+	return c
+}
+
+func sortedNodes(nodes map[string]*Node) []*Node {
+	sorted := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		sorted = append(sorted, node)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+func sortedEdges(edges map[string]*Edge) []*Edge {
+	sorted := make([]*Edge, 0, len(edges))
+	for _, edge := range edges {
+		sorted = append(sorted, edge)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
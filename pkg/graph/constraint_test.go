@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestUniqueConstraint tests that unique constraints reject duplicate values
+func TestUniqueConstraint(t *testing.T) {
+	g := NewGraph()
+	unique := Constraint{Kind: UniqueConstraint, Label: "Person", Property: "email"}
+	if err := g.CreateConstraint(unique); err != nil {
+		t.Fatalf("Failed to create constraint: %v", err)
+	}
+
+	if err := g.AddNode(&Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"email": "a@example.com"}}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	err := g.AddNode(&Node{ID: "B", Labels: []string{"Person"}, Properties: map[string]any{"email": "a@example.com"}})
+	var violation *ConstraintViolationError
+	if !errors.As(err, &violation) {
+		t.Fatalf("Expected constraint violation, got %v", err)
+	}
+	if violation.ElementID != "B" || violation.ConflictingID != "A" || violation.Constraint != unique {
+		t.Errorf("Unexpected violation details: %+v", violation)
+	}
+
+	// Nodes without the label are not constrained
+	if err := g.AddNode(&Node{ID: "C", Properties: map[string]any{"email": "a@example.com"}}); err != nil {
+		t.Errorf("Expected unlabelled node to be accepted: %v", err)
+	}
+
+	// Deleting the owner frees the value
+	g.DeleteNode("A")
+	if err := g.AddNode(&Node{ID: "B", Labels: []string{"Person"}, Properties: map[string]any{"email": "a@example.com"}}); err != nil {
+		t.Errorf("Expected value to be free after delete: %v", err)
+	}
+}
+
+// TestExistsAndTypeConstraints tests required and typed properties
+func TestExistsAndTypeConstraints(t *testing.T) {
+	g := NewGraph()
+	g.CreateConstraint(Constraint{Kind: ExistsConstraint, Label: "Person", Property: "name"})
+	g.CreateConstraint(Constraint{Kind: TypeConstraint, Label: "Person", Property: "age", Type: NumberProperty})
+
+	var violation *ConstraintViolationError
+	err := g.AddNode(&Node{ID: "A", Labels: []string{"Person"}})
+	if !errors.As(err, &violation) || violation.Constraint.Kind != ExistsConstraint {
+		t.Errorf("Expected exists violation, got %v", err)
+	}
+	err = g.AddNode(&Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"name": "Ann", "age": "old"}})
+	if !errors.As(err, &violation) || violation.Constraint.Kind != TypeConstraint {
+		t.Errorf("Expected type violation, got %v", err)
+	}
+	if err := g.AddNode(&Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"name": "Ann", "age": 40}}); err != nil {
+		t.Errorf("Expected valid node to be accepted: %v", err)
+	}
+}
+
+// TestConstraintViolationOrder tests that an element breaking several
+// constraints always reports the first in the order of Constraints
+func TestConstraintViolationOrder(t *testing.T) {
+	g := NewGraph()
+	for _, property := range []string{"d", "b", "a", "c"} {
+		g.CreateConstraint(Constraint{Kind: ExistsConstraint, Label: "Person", Property: property})
+	}
+
+	// Map iteration order varies, so repeat to catch nondeterminism
+	for i := 0; i < 20; i++ {
+		var violation *ConstraintViolationError
+		err := g.AddNode(&Node{ID: "A", Labels: []string{"Person"}})
+		if !errors.As(err, &violation) || violation.Constraint.Property != "a" {
+			t.Fatalf("Expected violation of the constraint on a, got %v", err)
+		}
+	}
+}
+
+// TestUniqueConstraintValues tests that NaN cannot be held unique and that
+// large integers are told apart
+func TestUniqueConstraintValues(t *testing.T) {
+	g := NewGraph()
+	if err := g.CreateConstraint(Constraint{Kind: UniqueConstraint, Property: "n"}); err != nil {
+		t.Fatalf("Failed to create constraint: %v", err)
+	}
+
+	var violation *ConstraintViolationError
+	if err := g.AddNode(&Node{ID: "A", Properties: map[string]any{"n": math.NaN()}}); !errors.As(err, &violation) {
+		t.Errorf("Expected NaN to violate the unique constraint, got %v", err)
+	}
+	if err := g.AddNode(&Node{ID: "B", Properties: map[string]any{"n": int64(1 << 53)}}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	if err := g.AddNode(&Node{ID: "C", Properties: map[string]any{"n": int64(1<<53 + 1)}}); err != nil {
+		t.Errorf("Expected a distinct large integer to be accepted: %v", err)
+	}
+	if err := g.AddNode(&Node{ID: "D", Properties: map[string]any{"n": float64(1 << 53)}}); !errors.As(err, &violation) || violation.ConflictingID != "B" {
+		t.Errorf("Expected an equal float to conflict with B, got %v", err)
+	}
+}
+
+// TestEdgeConstraint tests constraints on edge types
+func TestEdgeConstraint(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.CreateConstraint(Constraint{Kind: UniqueConstraint, Target: EdgeTarget, Label: "PAID", Property: "txn"})
+
+	if err := g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Type: "PAID", Properties: map[string]any{"txn": 1}}); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+	var violation *ConstraintViolationError
+	err := g.AddEdge(&Edge{ID: "e2", From: "B", To: "A", Type: "PAID", Properties: map[string]any{"txn": 1.0}})
+	if !errors.As(err, &violation) || violation.ConflictingID != "e1" {
+		t.Errorf("Expected unique violation against e1, got %v", err)
+	}
+	if err := g.AddEdge(&Edge{ID: "e3", From: "B", To: "A", Type: "KNOWS", Properties: map[string]any{"txn": 1}}); err != nil {
+		t.Errorf("Expected edge of other type to be accepted: %v", err)
+	}
+}
+
+// TestCreateConstraintValidatesExistingData tests that constraints cannot be
+// declared over data that already breaks them
+func TestCreateConstraintValidatesExistingData(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"email": "x"}})
+	g.AddNode(&Node{ID: "B", Labels: []string{"Person"}, Properties: map[string]any{"email": "x"}})
+
+	err := g.CreateConstraint(Constraint{Kind: UniqueConstraint, Label: "Person", Property: "email"})
+	var violation *ConstraintViolationError
+	if !errors.As(err, &violation) {
+		t.Fatalf("Expected constraint violation, got %v", err)
+	}
+	if len(g.Constraints()) != 0 {
+		t.Errorf("Expected failed constraint not to be declared")
+	}
+
+	c := Constraint{Kind: ExistsConstraint, Label: "Person", Property: "email"}
+	if err := g.CreateConstraint(c); err != nil {
+		t.Fatalf("Failed to create constraint: %v", err)
+	}
+	if err := g.CreateConstraint(c); err == nil {
+		t.Error("Expected error when creating duplicate constraint, got nil")
+	}
+	if err := g.DropConstraint(c); err != nil {
+		t.Errorf("Failed to drop constraint: %v", err)
+	}
+}
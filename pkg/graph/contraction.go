@@ -0,0 +1,467 @@
+package graph
+
+import (
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	pq "github.com/camwhite18/ArachneGraph/pkg/priority_queue"
+)
+
+// witnessLimit bounds the nodes a witness search settles while contracting a
+// node. A search cut short may add a shortcut that was not needed, which costs
+// query speed but never correctness.
+const witnessLimit = 500
+
+// chArc is an arc of a contraction hierarchy: an edge of the graph, or a
+// shortcut standing for the path From -> Via -> To.
+type chArc struct {
+	From, To string
+	Weight   float64
+	Via      string `json:",omitempty"`
+}
+
+// ContractionHierarchy answers shortest path queries on a graph that does not
+// change, many times faster than ShortestPath. Building it contracts the nodes
+// one by one in order of importance, adding shortcuts that preserve the
+// shortest paths between the nodes left; a query then only searches upwards
+// in that order from both ends.
+//
+// The hierarchy is a copy of the graph's structure at the time it is built,
+// and does not see later changes to the graph. It can be saved with
+// BoltPersist.SaveContractionHierarchy so it need not be rebuilt on every
+// start.
+type ContractionHierarchy struct {
+	rank map[string]int
+	arcs map[[2]string]*chArc
+	// up holds the arcs leaving each node to a node of higher rank, and down
+	// the arcs entering each node from a node of higher rank.
+	up   map[string][]*chArc
+	down map[string][]*chArc
+	// graph is the structureHash of the graph the hierarchy was built from.
+	graph uint64
+}
+
+// NewContractionHierarchy builds a contraction hierarchy from the graph, which
+// is read from a snapshot if it is a live Graph. Parallel edges are reduced to
+// the lightest, and self-loops, which are never part of a shortest path, are
+// dropped. It fails with ErrNegativeWeight if any edge has a negative weight.
+func NewContractionHierarchy(g Reader) (*ContractionHierarchy, error) {
+	return NewContractionHierarchyContext(context.Background(), g)
+}
+
+// NewContractionHierarchyContext is NewContractionHierarchy that gives up with
+// the context's error once the context is done.
+func NewContractionHierarchyContext(ctx context.Context, g Reader) (*ContractionHierarchy, error) {
+	if live, ok := g.(Graph); ok {
+		g = live.Snapshot()
+	}
+
+	b := &chBuilder{
+		out:        make(map[string]map[string]*chArc),
+		in:         make(map[string]map[string]*chArc),
+		contracted: make(map[string]int),
+		arcs:       make(map[[2]string]*chArc),
+		progress:   &search{ctx: ctx},
+	}
+	var nodes []string
+	for id := range g.AllNodes() {
+		nodes = append(nodes, id)
+		b.out[id] = make(map[string]*chArc)
+		b.in[id] = make(map[string]*chArc)
+	}
+	sort.Strings(nodes)
+	for _, edge := range g.AllEdges() {
+		if edge.Weight < 0 {
+			return nil, ErrNegativeWeight
+		}
+		if edge.From == edge.To {
+			continue
+		}
+		b.addArc(&chArc{From: edge.From, To: edge.To, Weight: edge.Weight})
+		if !edge.Directed {
+			b.addArc(&chArc{From: edge.To, To: edge.From, Weight: edge.Weight})
+		}
+	}
+
+	rank, err := b.contractAll(nodes)
+	if err != nil {
+		return nil, err
+	}
+	ch := newContractionHierarchy(rank, b.arcs)
+	ch.graph = structureHash(g)
+	return ch, nil
+}
+
+// structureHash returns a hash of the nodes and edges of g, leaving out their
+// labels, types and properties, which a contraction hierarchy does not use.
+func structureHash(g Reader) uint64 {
+	var nodes []string
+	for id := range g.AllNodes() {
+		nodes = append(nodes, id)
+	}
+	sort.Strings(nodes)
+	var edges []*Edge
+	for _, edge := range g.AllEdges() {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].ID < edges[j].ID })
+
+	h := fnv.New64a()
+	for _, id := range nodes {
+		h.Write([]byte(id + "\x00"))
+	}
+	for _, edge := range edges {
+		h.Write([]byte(edge.ID + "\x00" + edge.From + "\x00" + edge.To + "\x00"))
+		var buf [9]byte
+		binary.BigEndian.PutUint64(buf[:8], math.Float64bits(edge.Weight))
+		if edge.Directed {
+			buf[8] = 1
+		}
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// newContractionHierarchy splits the arcs of a contracted graph into the
+// upward and downward arcs searched by queries.
+func newContractionHierarchy(rank map[string]int, arcs map[[2]string]*chArc) *ContractionHierarchy {
+	ch := &ContractionHierarchy{
+		rank: rank,
+		arcs: arcs,
+		up:   make(map[string][]*chArc),
+		down: make(map[string][]*chArc),
+	}
+	for _, arc := range arcs {
+		if rank[arc.From] < rank[arc.To] {
+			ch.up[arc.From] = append(ch.up[arc.From], arc)
+		} else {
+			ch.down[arc.To] = append(ch.down[arc.To], arc)
+		}
+	}
+	// Sort the arcs so that ties between equal paths are broken the same
+	// way on every run
+	for _, arcs := range ch.up {
+		sort.Slice(arcs, func(i, j int) bool { return arcs[i].To < arcs[j].To })
+	}
+	for _, arcs := range ch.down {
+		sort.Slice(arcs, func(i, j int) bool { return arcs[i].From < arcs[j].From })
+	}
+	return ch
+}
+
+// chBuilder holds the graph left to contract while building a hierarchy.
+type chBuilder struct {
+	// out and in hold the lightest arc between each pair of nodes not yet
+	// contracted
+	out, in map[string]map[string]*chArc
+	// contracted counts the contracted neighbors of each node
+	contracted map[string]int
+	// arcs holds every arc ever added, which with the shortcuts is the
+	// hierarchy's search graph
+	arcs     map[[2]string]*chArc
+	progress *search
+}
+
+// addArc adds an arc unless the nodes are already joined by one as light.
+func (b *chBuilder) addArc(arc *chArc) {
+	if existing, ok := b.out[arc.From][arc.To]; ok && existing.Weight <= arc.Weight {
+		return
+	}
+	b.out[arc.From][arc.To] = arc
+	b.in[arc.To][arc.From] = arc
+	b.arcs[[2]string{arc.From, arc.To}] = arc
+}
+
+// contractAll contracts every node, least important first, and returns the
+// rank of each node in the order they were contracted.
+func (b *chBuilder) contractAll(nodes []string) (map[string]int, error) {
+	queue := make(pq.PriorityQueue, 0, len(nodes))
+	for _, id := range nodes {
+		shortcuts, err := b.shortcuts(id)
+		if err != nil {
+			return nil, err
+		}
+		heap.Push(&queue, &pq.PriorityQueueItem{NodeID: id, Distance: b.importance(id, shortcuts)})
+	}
+
+	rank := make(map[string]int, len(nodes))
+	for queue.Len() > 0 {
+		item := heap.Pop(&queue).(*pq.PriorityQueueItem)
+		id := item.NodeID
+
+		// Contracting its neighbors may have changed the node's importance
+		// since it was queued; recompute it and put the node back if
+		// another node is now less important
+		shortcuts, err := b.shortcuts(id)
+		if err != nil {
+			return nil, err
+		}
+		if importance := b.importance(id, shortcuts); queue.Len() > 0 && importance > queue[0].Distance {
+			heap.Push(&queue, &pq.PriorityQueueItem{NodeID: id, Distance: importance})
+			continue
+		}
+
+		for _, arc := range shortcuts {
+			b.addArc(arc)
+		}
+		for u := range b.in[id] {
+			delete(b.out[u], id)
+			b.contracted[u]++
+		}
+		for w := range b.out[id] {
+			delete(b.in[w], id)
+			b.contracted[w]++
+		}
+		delete(b.in, id)
+		delete(b.out, id)
+		rank[id] = len(rank)
+	}
+	return rank, nil
+}
+
+// importance orders the contraction: nodes whose contraction adds few
+// shortcuts relative to the arcs it removes go first, and contracted
+// neighbors push a node back to spread contraction evenly over the graph.
+func (b *chBuilder) importance(id string, shortcuts []*chArc) float64 {
+	return float64(len(shortcuts)-len(b.in[id])-len(b.out[id])) + float64(b.contracted[id])
+}
+
+// shortcuts returns the shortcuts contracting a node would need: one for each
+// pair of neighbors whose shortest path runs through it.
+func (b *chBuilder) shortcuts(id string) ([]*chArc, error) {
+	var shortcuts []*chArc
+	for _, u := range sortedKeys(b.in[id]) {
+		into := b.in[id][u]
+		limit := 0.0
+		for w, out := range b.out[id] {
+			if w != u {
+				limit = math.Max(limit, into.Weight+out.Weight)
+			}
+		}
+		witness, err := b.witness(u, id, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range sortedKeys(b.out[id]) {
+			if w == u {
+				continue
+			}
+			weight := into.Weight + b.out[id][w].Weight
+			if d, ok := witness[w]; ok && d <= weight {
+				continue
+			}
+			shortcuts = append(shortcuts, &chArc{From: u, To: w, Weight: weight, Via: id})
+		}
+	}
+	return shortcuts, nil
+}
+
+// witness searches for paths from source that avoid a node, up to a distance
+// limit, and returns the distances found.
+func (b *chBuilder) witness(source, avoid string, limit float64) (map[string]float64, error) {
+	dist := map[string]float64{source: 0}
+	settled := make(map[string]bool)
+	queue := make(pq.PriorityQueue, 0)
+	heap.Push(&queue, &pq.PriorityQueueItem{NodeID: source, Distance: 0})
+
+	for queue.Len() > 0 && len(settled) < witnessLimit {
+		item := heap.Pop(&queue).(*pq.PriorityQueueItem)
+		current := item.NodeID
+		if settled[current] {
+			continue
+		}
+		if item.Distance > limit {
+			break
+		}
+		if err := b.progress.step(); err != nil {
+			return nil, err
+		}
+		settled[current] = true
+		for next, arc := range b.out[current] {
+			if next == avoid {
+				continue
+			}
+			alt := dist[current] + arc.Weight
+			if d, ok := dist[next]; !ok || alt < d {
+				dist[next] = alt
+				heap.Push(&queue, &pq.PriorityQueueItem{NodeID: next, Distance: alt})
+			}
+		}
+	}
+	return dist, nil
+}
+
+func sortedKeys(m map[string]*chArc) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// chSearch is one half of a hierarchy query, searching upwards from one end.
+type chSearch struct {
+	dist    map[string]float64
+	prev    map[string]*chArc
+	settled map[string]bool
+	queue   pq.PriorityQueue
+}
+
+func newCHSearch(start string) *chSearch {
+	s := &chSearch{
+		dist:    map[string]float64{start: 0},
+		prev:    make(map[string]*chArc),
+		settled: make(map[string]bool),
+	}
+	heap.Push(&s.queue, &pq.PriorityQueueItem{NodeID: start, Distance: 0})
+	return s
+}
+
+func (s *chSearch) top() float64 {
+	if s.queue.Len() == 0 {
+		return math.Inf(1)
+	}
+	return s.queue[0].Distance
+}
+
+// ShortestPath finds the shortest path from source to target in the graph the
+// hierarchy was built from, returning the same as the graph's ShortestPath.
+func (ch *ContractionHierarchy) ShortestPath(source, target string) ([]string, float64, error) {
+	if _, exists := ch.rank[source]; !exists {
+		return nil, 0, fmt.Errorf("source node %q not found", source)
+	}
+	if _, exists := ch.rank[target]; !exists {
+		return nil, 0, fmt.Errorf("target node %q not found", target)
+	}
+
+	forward, backward := newCHSearch(source), newCHSearch(target)
+	best := math.Inf(1)
+	meet := ""
+	if source == target {
+		best, meet = 0, source
+	}
+
+	// Unlike a plain bidirectional search, each side must go on until it
+	// alone cannot beat the best path, since the two only meet at the
+	// highest ranked node of the path
+	for forward.top() < best || backward.top() < best {
+		s, other, arcs := forward, backward, ch.up
+		if backward.top() < forward.top() {
+			s, other, arcs = backward, forward, ch.down
+		}
+
+		item := heap.Pop(&s.queue).(*pq.PriorityQueueItem)
+		current := item.NodeID
+		if s.settled[current] || item.Distance > s.dist[current] {
+			continue
+		}
+		s.settled[current] = true
+
+		for _, arc := range arcs[current] {
+			next := arc.To
+			if s == backward {
+				next = arc.From
+			}
+			alt := s.dist[current] + arc.Weight
+			if d, reached := s.dist[next]; !reached || alt < d {
+				s.dist[next] = alt
+				s.prev[next] = arc
+				heap.Push(&s.queue, &pq.PriorityQueueItem{NodeID: next, Distance: alt})
+			}
+			if d, reached := other.dist[next]; reached && alt+d < best {
+				best, meet = alt+d, next
+			}
+		}
+	}
+
+	if meet == "" {
+		return nil, 0, fmt.Errorf("no path found from %q to %q", source, target)
+	}
+
+	// Collect the arcs from source to the meeting node and on to target,
+	// then expand the shortcuts among them
+	var arcs []*chArc
+	for current := meet; current != source; current = forward.prev[current].From {
+		arcs = append(arcs, forward.prev[current])
+	}
+	reverse(arcs)
+	for current := meet; current != target; current = backward.prev[current].To {
+		arcs = append(arcs, backward.prev[current])
+	}
+	path := []string{source}
+	for _, arc := range arcs {
+		path = ch.unpack(path, arc)
+	}
+	return path, best, nil
+}
+
+// unpack appends the nodes an arc leads through after its From node.
+func (ch *ContractionHierarchy) unpack(path []string, arc *chArc) []string {
+	if arc.Via == "" {
+		return append(path, arc.To)
+	}
+	path = ch.unpack(path, ch.arcs[[2]string{arc.From, arc.Via}])
+	return ch.unpack(path, ch.arcs[[2]string{arc.Via, arc.To}])
+}
+
+// savedHierarchy is the form a contraction hierarchy is serialized in.
+type savedHierarchy struct {
+	Ranks map[string]int
+	Arcs  []*chArc
+	Graph uint64 `json:",omitempty"`
+}
+
+func (ch *ContractionHierarchy) MarshalJSON() ([]byte, error) {
+	saved := savedHierarchy{Ranks: ch.rank, Arcs: make([]*chArc, 0, len(ch.arcs)), Graph: ch.graph}
+	for _, arc := range ch.arcs {
+		saved.Arcs = append(saved.Arcs, arc)
+	}
+	sort.Slice(saved.Arcs, func(i, j int) bool {
+		a, b := saved.Arcs[i], saved.Arcs[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return json.Marshal(saved)
+}
+
+func (ch *ContractionHierarchy) UnmarshalJSON(data []byte) error {
+	var saved savedHierarchy
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	arcs := make(map[[2]string]*chArc, len(saved.Arcs))
+	for _, arc := range saved.Arcs {
+		if _, ok := saved.Ranks[arc.From]; !ok {
+			return fmt.Errorf("arc from unknown node %q", arc.From)
+		}
+		if _, ok := saved.Ranks[arc.To]; !ok {
+			return fmt.Errorf("arc to unknown node %q", arc.To)
+		}
+		arcs[[2]string{arc.From, arc.To}] = arc
+	}
+	// A shortcut is unpacked into the arcs to and from its Via node, which
+	// was contracted before either end
+	for _, arc := range saved.Arcs {
+		if arc.Via == "" {
+			continue
+		}
+		if arcs[[2]string{arc.From, arc.Via}] == nil || arcs[[2]string{arc.Via, arc.To}] == nil {
+			return fmt.Errorf("shortcut from %q to %q via %q is missing an arc", arc.From, arc.To, arc.Via)
+		}
+		if rank := saved.Ranks[arc.Via]; rank >= saved.Ranks[arc.From] || rank >= saved.Ranks[arc.To] {
+			return fmt.Errorf("shortcut from %q to %q via %q does not go through a lower ranked node", arc.From, arc.To, arc.Via)
+		}
+	}
+	*ch = *newContractionHierarchy(saved.Ranks, arcs)
+	ch.graph = saved.Graph
+	return nil
+}
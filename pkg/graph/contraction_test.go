@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// checkHierarchy compares every query of a hierarchy with the graph's ShortestPath
+func checkHierarchy(t *testing.T, g Graph, ch *ContractionHierarchy) {
+	t.Helper()
+	var nodes []string
+	for id := range g.AllNodes() {
+		nodes = append(nodes, id)
+	}
+	for _, from := range nodes {
+		for _, to := range nodes {
+			_, want, wantErr := g.ShortestPath(from, to)
+			path, got, err := ch.ShortestPath(from, to)
+			if (err != nil) != (wantErr != nil) {
+				t.Fatalf("Expected error %v from %s to %s, got %v", wantErr, from, to, err)
+			}
+			if err != nil {
+				continue
+			}
+			if math.Abs(got-want) > 1e-9 {
+				t.Fatalf("Expected distance %f from %s to %s, got %f", want, from, to, got)
+			}
+			sum := 0.0
+			for i := 0; i+1 < len(path); i++ {
+				edges, _ := g.EdgesBetween(path[i], path[i+1])
+				if len(edges) == 0 {
+					t.Fatalf("Expected an edge from %s to %s in path %v", path[i], path[i+1], path)
+				}
+				sum += edges[0].Weight
+			}
+			if path[0] != from || path[len(path)-1] != to || math.Abs(sum-got) > 1e-9 {
+				t.Fatalf("Expected a path from %s to %s of weight %f, got %v of weight %f", from, to, got, path, sum)
+			}
+		}
+	}
+}
+
+// TestContractionHierarchy tests hierarchy queries against Dijkstra on every pair
+func TestContractionHierarchy(t *testing.T) {
+	for _, d := range []Directedness{DirectedGraph, UndirectedGraph} {
+		g := newGrid(t, 6, WithDirectedness(d))
+		ch, err := NewContractionHierarchy(g)
+		if err != nil {
+			t.Fatalf("Failed to build contraction hierarchy: %v", err)
+		}
+		checkHierarchy(t, g, ch)
+	}
+
+	// One-way streets and unreachable nodes
+	g := newYenGraph(t)
+	g.AddNode(&Node{ID: "X"})
+	ch, err := NewContractionHierarchy(g)
+	if err != nil {
+		t.Fatalf("Failed to build contraction hierarchy: %v", err)
+	}
+	checkHierarchy(t, g, ch)
+	if _, _, err := ch.ShortestPath("C", "nowhere"); err == nil {
+		t.Errorf("Expected error for a missing target")
+	}
+
+	if _, err := NewContractionHierarchy(newArbitrageGraph(t)); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Expected ErrNegativeWeight, got %v", err)
+	}
+}
+
+// TestBoltPersistContractionHierarchy tests that a hierarchy survives a save and load
+func TestBoltPersistContractionHierarchy(t *testing.T) {
+	persister := &BoltPersist{Path: filepath.Join(t.TempDir(), "graph.db")}
+	if _, err := persister.LoadContractionHierarchy(); err == nil {
+		t.Errorf("Expected error when no hierarchy was saved")
+	}
+
+	g := newGrid(t, 5)
+	ch, err := NewContractionHierarchy(g)
+	if err != nil {
+		t.Fatalf("Failed to build contraction hierarchy: %v", err)
+	}
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	if err := persister.SaveContractionHierarchy(ch); err != nil {
+		t.Fatalf("Failed to save contraction hierarchy: %v", err)
+	}
+
+	loaded, err := persister.LoadContractionHierarchy()
+	if err != nil {
+		t.Fatalf("Failed to load contraction hierarchy: %v", err)
+	}
+	checkHierarchy(t, g, loaded)
+
+	// Saving the graph again leaves the hierarchy in place
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	if _, err := persister.LoadContractionHierarchy(); err != nil {
+		t.Errorf("Failed to load contraction hierarchy after saving the graph: %v", err)
+	}
+
+	// Saving a changed graph leaves the hierarchy out of date
+	g.SetEdgeWeight("e1", 100)
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	if _, err := persister.LoadContractionHierarchy(); err == nil {
+		t.Errorf("Expected error when the saved graph has changed")
+	}
+}
+
+// TestUnmarshalContractionHierarchy tests that a saved hierarchy is checked before use
+func TestUnmarshalContractionHierarchy(t *testing.T) {
+	for _, data := range []string{
+		`{"Ranks":{"A":0,"B":1,"C":2},"Arcs":[{"From":"A","To":"C","Weight":2,"Via":"B"}]}`,
+		`{"Ranks":{"A":0,"B":1,"C":2},"Arcs":[{"From":"A","To":"B","Weight":1},{"From":"B","To":"C","Weight":1},{"From":"A","To":"C","Weight":2,"Via":"B"}]}`,
+		`{"Ranks":{"A":0},"Arcs":[{"From":"A","To":"B","Weight":1}]}`,
+	} {
+		var ch ContractionHierarchy
+		if err := json.Unmarshal([]byte(data), &ch); err == nil {
+			t.Errorf("Expected error unmarshalling %s", data)
+		}
+	}
+}
+
+func BenchmarkContractionHierarchy(b *testing.B) {
+	g := newGrid(b, 100)
+	ch, err := NewContractionHierarchy(g)
+	if err != nil {
+		b.Fatalf("Failed to build contraction hierarchy: %v", err)
+	}
+	target := fmt.Sprintf("%d,%d", 50, 99)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ch.ShortestPath("0,0", target); err != nil {
+			b.Fatalf("Failed to find path: %v", err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package graph
+
+import "maps"
+
+// cowMap is a map that a store shares copy-on-write with its snapshots. Each
+// map remembers the store epoch in which it was last copied; a store writing
+// in a later epoch copies the map first, leaving the version seen by older
+// snapshots untouched. Reads go straight to m.
+type cowMap[K comparable, V any] struct {
+	m     map[K]V
+	epoch uint64
+}
+
+func newCowMap[K comparable, V any](epoch uint64) cowMap[K, V] {
+	return cowMap[K, V]{m: make(map[K]V), epoch: epoch}
+}
+
+// mut returns a version of the map that may be written in the given epoch.
+func (c *cowMap[K, V]) mut(epoch uint64) map[K]V {
+	if c.epoch != epoch {
+		if c.m == nil {
+			c.m = make(map[K]V)
+		} else {
+			c.m = maps.Clone(c.m)
+		}
+		c.epoch = epoch
+	}
+	return c.m
+}
+
+// setInner sets a value in the inner map stored under outerKey, creating the
+// inner map if needed.
+func setInner[K1, K2 comparable, V any](c *cowMap[K1, cowMap[K2, V]], epoch uint64, outerKey K1, key K2, value V) {
+	outer := c.mut(epoch)
+	inner := outer[outerKey]
+	inner.mut(epoch)[key] = value
+	outer[outerKey] = inner
+}
+
+// deleteInner deletes a value from the inner map stored under outerKey,
+// dropping the inner map once it is empty.
+func deleteInner[K1, K2 comparable, V any](c *cowMap[K1, cowMap[K2, V]], epoch uint64, outerKey K1, key K2) {
+	inner, exists := c.m[outerKey]
+	if !exists {
+		return
+	}
+	if _, exists := inner.m[key]; !exists {
+		return
+	}
+	outer := c.mut(epoch)
+	m := inner.mut(epoch)
+	delete(m, key)
+	if len(m) == 0 {
+		delete(outer, outerKey)
+	} else {
+		outer[outerKey] = inner
+	}
+}
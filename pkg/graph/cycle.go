@@ -0,0 +1,263 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+)
+
+// FindCycle returns a cycle of the graph as a path that starts and ends at the
+// same node, or an error if there is none. A self-loop is a cycle of one
+// edge. A cycle never follows the same edge twice, so unlike with IsDAG, a
+// single undirected edge is not a cycle, though two parallel ones are.
+func (s *store) FindCycle() (*Path, error) {
+	return s.FindCycleContext(context.Background())
+}
+
+// FindCycleContext is FindCycle that gives up with the context's error once
+// the context is done, or with ErrVisitLimit once it visits more nodes than
+// allowed.
+func (s *store) FindCycleContext(ctx context.Context) (*Path, error) {
+	cycle, err := s.findCycle(s.newSearch(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if cycle == nil {
+		return nil, fmt.Errorf("no cycle found")
+	}
+	return cycle, nil
+}
+
+// HasCycle reports whether the graph has a cycle as FindCycle sees it. Having
+// no way to report ErrVisitLimit, it is not bound by WithVisitLimit;
+// HasCycleContext is.
+func (s *store) HasCycle() bool {
+	cycle, _ := s.findCycle(s.newSearch(context.Background()).unlimited())
+	return cycle != nil
+}
+
+// HasCycleContext is HasCycle that gives up with the context's error once the
+// context is done, or with ErrVisitLimit once it visits more nodes than
+// allowed.
+func (s *store) HasCycleContext(ctx context.Context) (bool, error) {
+	cycle, err := s.findCycle(s.newSearch(ctx))
+	return cycle != nil, err
+}
+
+// findCycle runs a depth-first search from each node in turn by ID, returning
+// the first cycle closed by an edge back to a node still on the stack, or nil
+// if there is none.
+func (s *store) findCycle(search *search) (*Path, error) {
+	type frame struct {
+		id    string
+		via   *Edge
+		edges []*Edge
+		next  int
+	}
+	const (
+		onStack = 1
+		done    = 2
+	)
+
+	state := make(map[string]int, len(s.nodes.m))
+	for _, root := range sortedNodes(s.nodes.m) {
+		if state[root.ID] != 0 {
+			continue
+		}
+		if err := search.visit(); err != nil {
+			return nil, err
+		}
+		state[root.ID] = onStack
+		stack := []*frame{{id: root.ID, edges: s.adjacent(s.out.m[root.ID])}}
+
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.next == len(top.edges) {
+				state[top.id] = done
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			edge := top.edges[top.next]
+			top.next++
+			// Going back along the edge just followed is not a cycle
+			if top.via != nil && edge.ID == top.via.ID {
+				continue
+			}
+
+			next := edge.Other(top.id)
+			switch state[next] {
+			case onStack:
+				i := len(stack) - 1
+				for stack[i].id != next {
+					i--
+				}
+				cycle := &Path{Nodes: []string{next}}
+				for _, f := range stack[i+1:] {
+					cycle.Nodes = append(cycle.Nodes, f.id)
+					cycle.Edges = append(cycle.Edges, f.via.clone())
+				}
+				cycle.Nodes = append(cycle.Nodes, next)
+				cycle.Edges = append(cycle.Edges, edge.clone())
+				for _, e := range cycle.Edges {
+					cycle.Weight += e.Weight
+				}
+				return cycle, nil
+			case 0:
+				if err := search.visit(); err != nil {
+					return nil, err
+				}
+				state[next] = onStack
+				stack = append(stack, &frame{id: next, via: edge, edges: s.adjacent(s.out.m[next])})
+			}
+		}
+	}
+	return nil, nil
+}
+
+// SimpleCycles lists the elementary cycles of the graph, those that pass
+// through no node twice, using Johnson's algorithm. Each cycle starts and
+// ends at its node with the lowest ID, and the cycles come in order of that
+// node. Parallel edges give rise to distinct cycles, a self-loop is a cycle
+// of one edge, and a cycle of undirected edges is listed in one direction
+// only. A limit above zero stops the listing after that many cycles, as
+// there can be exponentially many.
+func (s *store) SimpleCycles(limit int) ([]*Path, error) {
+	return s.SimpleCyclesContext(context.Background(), limit)
+}
+
+// SimpleCyclesContext is SimpleCycles that gives up with the context's error
+// once the context is done, or with ErrVisitLimit once its searches visit
+// more nodes in total than allowed.
+func (s *store) SimpleCyclesContext(ctx context.Context, limit int) ([]*Path, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must not be negative, got %d", limit)
+	}
+
+	f := &cycleFinder{
+		s:      s,
+		search: s.newSearch(ctx),
+		limit:  limit,
+		done:   make(map[string]bool, len(s.nodes.m)),
+	}
+	for _, node := range sortedNodes(s.nodes.m) {
+		// Only cycles through nodes not yet used as a start are new
+		f.start = node.ID
+		f.blocked = make(map[string]bool)
+		f.blockers = make(map[string]map[string]bool)
+		if _, err := f.circuit(node.ID); err != nil {
+			return nil, err
+		}
+		if f.full() {
+			break
+		}
+		f.done[node.ID] = true
+	}
+	return f.cycles, nil
+}
+
+// cycleFinder holds the state of Johnson's algorithm.
+type cycleFinder struct {
+	s      *store
+	search *search
+	limit  int
+	// start is the node every cycle in the current round begins at, and done
+	// holds the nodes of earlier rounds
+	start string
+	done  map[string]bool
+	// A blocked node cannot reach start without passing through the path
+	// again; blockers[w] holds the nodes to unblock when w is unblocked
+	blocked  map[string]bool
+	blockers map[string]map[string]bool
+	// nodes and edges are the path from start being extended
+	nodes  []string
+	edges  []*Edge
+	cycles []*Path
+}
+
+func (f *cycleFinder) full() bool {
+	return f.limit > 0 && len(f.cycles) >= f.limit
+}
+
+// circuit extends the path to v, recording every cycle back to start through
+// it, and reports whether it found any.
+func (f *cycleFinder) circuit(v string) (bool, error) {
+	if err := f.search.visit(); err != nil {
+		return false, err
+	}
+	found := false
+	f.blocked[v] = true
+	f.nodes = append(f.nodes, v)
+	defer func() { f.nodes = f.nodes[:len(f.nodes)-1] }()
+
+	edges := f.s.adjacent(f.s.out.m[v])
+	for _, edge := range edges {
+		w := edge.Other(v)
+		switch {
+		case f.done[w]:
+		case w == f.start:
+			// Going back along the edge just followed is not a cycle
+			if n := len(f.edges); n > 0 && f.edges[n-1].ID == edge.ID {
+				continue
+			}
+			found = true
+			f.record(edge)
+		case !f.blocked[w]:
+			f.edges = append(f.edges, edge)
+			ok, err := f.circuit(w)
+			f.edges = f.edges[:len(f.edges)-1]
+			if err != nil {
+				return false, err
+			}
+			found = found || ok
+		}
+		if f.full() {
+			return found, nil
+		}
+	}
+
+	if found {
+		f.unblock(v)
+		return true, nil
+	}
+	// v stays blocked until one of its neighbors finds its way to start
+	for _, edge := range edges {
+		w := edge.Other(v)
+		if f.blockers[w] == nil {
+			f.blockers[w] = make(map[string]bool)
+		}
+		f.blockers[w][v] = true
+	}
+	return false, nil
+}
+
+func (f *cycleFinder) unblock(v string) {
+	f.blocked[v] = false
+	for w := range f.blockers[v] {
+		delete(f.blockers[v], w)
+		if f.blocked[w] {
+			f.unblock(w)
+		}
+	}
+}
+
+// record adds the path closed by edge as a cycle, unless it is the reverse of
+// one already found.
+func (f *cycleFinder) record(edge *Edge) {
+	edges := append(f.edges[:len(f.edges):len(f.edges)], edge)
+
+	// A cycle of undirected edges is found once in each direction; keep the
+	// one whose first edge has the lower ID
+	undirected := true
+	for _, e := range edges {
+		undirected = undirected && !e.Directed
+	}
+	if undirected && len(edges) > 1 && edges[0].ID > edges[len(edges)-1].ID {
+		return
+	}
+
+	cycle := &Path{Nodes: append(append([]string{}, f.nodes...), f.start)}
+	for _, e := range edges {
+		cycle.Edges = append(cycle.Edges, e.clone())
+		cycle.Weight += e.Weight
+	}
+	f.cycles = append(f.cycles, cycle)
+}
@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// newCyclicGraph builds a directed graph with three elementary cycles:
+//
+//	A --> B --> C --> A
+//	C --> D --> C
+//	D --> D
+func newCyclicGraph(t *testing.T) Graph {
+	t.Helper()
+	return buildGraph(t, nodesWithIDs("A", "B", "C", "D"), []*Edge{
+		{ID: "e1", From: "A", To: "B", Weight: 1.0},
+		{ID: "e2", From: "B", To: "C", Weight: 2.0},
+		{ID: "e3", From: "C", To: "A", Weight: 3.0},
+		{ID: "e4", From: "C", To: "D", Weight: 1.0},
+		{ID: "e5", From: "D", To: "C", Weight: 1.0},
+		{ID: "e6", From: "D", To: "D", Weight: 1.0},
+	})
+}
+
+// TestFindCycle tests finding one cycle as a path of edges
+func TestFindCycle(t *testing.T) {
+	g := newCyclicGraph(t)
+
+	cycle, err := g.FindCycle()
+	if err != nil {
+		t.Fatalf("FindCycle failed: %v", err)
+	}
+	if want := []string{"A", "B", "C", "A"}; !reflect.DeepEqual(cycle.Nodes, want) {
+		t.Errorf("Expected cycle %v, got %v", want, cycle.Nodes)
+	}
+	if want := []string{"e1", "e2", "e3"}; !reflect.DeepEqual(edgeIDs(cycle.Edges), want) {
+		t.Errorf("Expected cycle edges %v, got %v", want, edgeIDs(cycle.Edges))
+	}
+	if cycle.Weight != 6 {
+		t.Errorf("Expected cycle weight 6, got %f", cycle.Weight)
+	}
+	if !g.HasCycle() {
+		t.Errorf("Expected the graph to have a cycle")
+	}
+
+	if _, err := newDiamond(t).FindCycle(); err == nil {
+		t.Errorf("Expected error for a graph without cycles")
+	}
+	if newDiamond(t).HasCycle() {
+		t.Errorf("Expected the diamond to have no cycle")
+	}
+}
+
+// TestFindCycleSelfLoop tests that a self-loop is a cycle of one edge
+func TestFindCycleSelfLoop(t *testing.T) {
+	g := newChain(t, 3)
+	if err := g.AddEdge(&Edge{ID: "loop", From: "n2", To: "n2", Weight: 1.0}); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+
+	cycle, err := g.FindCycle()
+	if err != nil {
+		t.Fatalf("FindCycle failed: %v", err)
+	}
+	if want := []string{"n2", "n2"}; !reflect.DeepEqual(cycle.Nodes, want) {
+		t.Errorf("Expected cycle %v, got %v", want, cycle.Nodes)
+	}
+	if want := []string{"loop"}; !reflect.DeepEqual(edgeIDs(cycle.Edges), want) {
+		t.Errorf("Expected cycle edges %v, got %v", want, edgeIDs(cycle.Edges))
+	}
+}
+
+// TestFindCycleUndirected tests that an undirected edge is not followed back on itself
+func TestFindCycleUndirected(t *testing.T) {
+	if g := newChain(t, 5, WithDirectedness(UndirectedGraph)); g.HasCycle() {
+		t.Errorf("Expected an undirected chain to have no cycle")
+	}
+
+	g := newTriangle(t, UndirectedGraph)
+	cycle, err := g.FindCycle()
+	if err != nil {
+		t.Fatalf("FindCycle failed: %v", err)
+	}
+	if want := []string{"A", "B", "C", "A"}; !reflect.DeepEqual(cycle.Nodes, want) {
+		t.Errorf("Expected cycle %v, got %v", want, cycle.Nodes)
+	}
+	if newTriangle(t, DirectedGraph).HasCycle() {
+		t.Errorf("Expected a directed triangle to have no cycle")
+	}
+
+	// Parallel undirected edges make a cycle of two
+	parallel := newChain(t, 2, WithDirectedness(UndirectedGraph))
+	if err := parallel.AddEdge(&Edge{ID: "e1b", From: "n0", To: "n1", Weight: 1.0}); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+	cycles, err := parallel.SimpleCycles(0)
+	if err != nil {
+		t.Fatalf("SimpleCycles failed: %v", err)
+	}
+	if len(cycles) != 1 || !reflect.DeepEqual(edgeIDs(cycles[0].Edges), []string{"e1", "e1b"}) {
+		t.Errorf("Expected one cycle through e1 and e1b, got %v", cycles)
+	}
+}
+
+// TestSimpleCycles tests listing every elementary cycle
+func TestSimpleCycles(t *testing.T) {
+	g := newCyclicGraph(t)
+
+	cycles, err := g.SimpleCycles(0)
+	if err != nil {
+		t.Fatalf("SimpleCycles failed: %v", err)
+	}
+	want := [][]string{{"e1", "e2", "e3"}, {"e4", "e5"}, {"e6"}}
+	if len(cycles) != len(want) {
+		t.Fatalf("Expected %d cycles, got %d", len(want), len(cycles))
+	}
+	for i, cycle := range cycles {
+		if !reflect.DeepEqual(edgeIDs(cycle.Edges), want[i]) {
+			t.Errorf("Expected cycle %d to follow %v, got %v", i, want[i], edgeIDs(cycle.Edges))
+		}
+		if cycle.Nodes[0] != cycle.Nodes[len(cycle.Nodes)-1] {
+			t.Errorf("Expected cycle %d to end where it starts, got %v", i, cycle.Nodes)
+		}
+	}
+
+	// An undirected triangle is listed once, not once each way
+	cycles, err = newTriangle(t, UndirectedGraph).SimpleCycles(0)
+	if err != nil {
+		t.Fatalf("SimpleCycles failed: %v", err)
+	}
+	if len(cycles) != 1 || !reflect.DeepEqual(cycles[0].Nodes, []string{"A", "B", "C", "A"}) {
+		t.Errorf("Expected one undirected cycle, got %v", cycles)
+	}
+}
+
+// TestSimpleCyclesComplete tests the number of cycles in a complete directed graph
+func TestSimpleCyclesComplete(t *testing.T) {
+	g := NewGraph()
+	ids := []string{"A", "B", "C", "D"}
+	for _, id := range ids {
+		if err := g.AddNode(&Node{ID: id}); err != nil {
+			t.Fatalf("Failed to add node: %v", err)
+		}
+	}
+	for _, from := range ids {
+		for _, to := range ids {
+			if from != to {
+				if err := g.AddEdge(&Edge{ID: fmt.Sprintf("%s%s", from, to), From: from, To: to, Weight: 1.0}); err != nil {
+					t.Fatalf("Failed to add edge: %v", err)
+				}
+			}
+		}
+	}
+
+	// 6 cycles of two nodes, 8 of three and 6 of four
+	cycles, err := g.SimpleCycles(0)
+	if err != nil {
+		t.Fatalf("SimpleCycles failed: %v", err)
+	}
+	if len(cycles) != 20 {
+		t.Errorf("Expected 20 cycles, got %d", len(cycles))
+	}
+	seen := make(map[string]bool)
+	for _, cycle := range cycles {
+		key := pathKey(cycle)
+		if seen[key] {
+			t.Errorf("Expected each cycle once, got %v twice", cycle.Nodes)
+		}
+		seen[key] = true
+	}
+
+	cycles, err = g.SimpleCycles(5)
+	if err != nil {
+		t.Fatalf("SimpleCycles failed: %v", err)
+	}
+	if len(cycles) != 5 {
+		t.Errorf("Expected the limit to stop at 5 cycles, got %d", len(cycles))
+	}
+	if _, err := g.SimpleCycles(-1); err == nil {
+		t.Errorf("Expected error for a negative limit")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.SimpleCyclesContext(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected SimpleCycles to be cancelled, got %v", err)
+	}
+	if _, err := g.FindCycleContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected FindCycle to be cancelled, got %v", err)
+	}
+
+	// HasCycle cannot report the limit, so it is not bound by it
+	chain := newChain(t, 10, WithVisitLimit(5))
+	if chain.HasCycle() {
+		t.Errorf("Expected the chain to have no cycle")
+	}
+	if _, err := chain.HasCycleContext(context.Background()); !errors.Is(err, ErrVisitLimit) {
+		t.Errorf("Expected HasCycleContext to hit the visit limit, got %v", err)
+	}
+	if found, err := newCyclicGraph(t).HasCycleContext(context.Background()); !found || err != nil {
+		t.Errorf("Expected HasCycleContext to find a cycle, got %v (%v)", found, err)
+	}
+}
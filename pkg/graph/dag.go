@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CycleError is returned when an operation needs a graph without cycles but
+// finds one. Cycle starts and ends at the same node.
+type CycleError struct {
+	Cycle *Path
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("graph has a cycle %s", strings.Join(e.Cycle.Nodes, " -> "))
+}
+
+// TopologicalSort orders the nodes so that every edge leads from a node to a
+// later one, using Kahn's algorithm. The order is the waves of
+// TopologicalWaves one after the other. If the graph is not a directed
+// acyclic graph it fails with a *CycleError holding one of its cycles. An
+// undirected edge orders neither of its endpoints before the other, so it
+// counts as a cycle between them.
+func (s *store) TopologicalSort() ([]string, error) {
+	return s.TopologicalSortContext(context.Background())
+}
+
+// TopologicalSortContext is TopologicalSort that gives up with the context's
+// error once the context is done, or with ErrVisitLimit once it orders more
+// nodes than allowed.
+func (s *store) TopologicalSortContext(ctx context.Context) ([]string, error) {
+	waves, err := s.kahn(s.newSearch(ctx))
+	if err != nil {
+		return nil, err
+	}
+	order := make([]string, 0, len(s.nodes.m))
+	for _, wave := range waves {
+		order = append(order, wave...)
+	}
+	return order, nil
+}
+
+// TopologicalWaves groups the nodes into waves that can be scheduled one
+// after the other, the nodes of each wave in parallel: the first wave holds
+// the nodes without incoming edges, and each later wave the nodes whose
+// incoming edges all come from earlier waves. Each wave is sorted by ID. It
+// fails like TopologicalSort if the graph has a cycle.
+func (s *store) TopologicalWaves() ([][]string, error) {
+	return s.TopologicalWavesContext(context.Background())
+}
+
+// TopologicalWavesContext is TopologicalWaves that gives up with the
+// context's error once the context is done, or with ErrVisitLimit once it
+// orders more nodes than allowed.
+func (s *store) TopologicalWavesContext(ctx context.Context) ([][]string, error) {
+	return s.kahn(s.newSearch(ctx))
+}
+
+// IsDAG reports whether the graph is a directed acyclic graph. Having no way
+// to report ErrVisitLimit, it is not bound by WithVisitLimit; IsDAGContext
+// is.
+func (s *store) IsDAG() bool {
+	dag, _ := s.isDAG(s.newSearch(context.Background()).unlimited())
+	return dag
+}
+
+// IsDAGContext is IsDAG that gives up with the context's error once the
+// context is done, or with ErrVisitLimit once it orders more nodes than
+// allowed.
+func (s *store) IsDAGContext(ctx context.Context) (bool, error) {
+	return s.isDAG(s.newSearch(ctx))
+}
+
+func (s *store) isDAG(search *search) (bool, error) {
+	_, err := s.kahn(search)
+	var cycleErr *CycleError
+	if errors.As(err, &cycleErr) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// LongestPath returns the heaviest path in a directed acyclic graph by the
+// weights of its edges: the critical path when edges are dependencies
+// weighted by duration. Paths may start at any node, so with no edges of
+// positive weight the path is a single node. It fails like TopologicalSort
+// if the graph has a cycle.
+func (s *store) LongestPath() (*Path, error) {
+	return s.LongestPathContext(context.Background())
+}
+
+// LongestPathContext is LongestPath that gives up with the context's error
+// once the context is done, or with ErrVisitLimit once it orders more nodes
+// than allowed.
+func (s *store) LongestPathContext(ctx context.Context) (*Path, error) {
+	if len(s.nodes.m) == 0 {
+		return nil, fmt.Errorf("graph has no nodes")
+	}
+	order, err := s.TopologicalSortContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Going through the nodes in order, the heaviest path to each node is
+	// known before any edge leaving it is followed
+	tree := &PathTree{Distances: make(map[string]float64, len(order)), Predecessors: make(map[string]*Edge)}
+	for _, id := range order {
+		tree.Distances[id] = 0
+	}
+	end := order[0]
+	for _, id := range order {
+		d := tree.Distances[id]
+		if d > tree.Distances[end] {
+			end = id
+		}
+		for _, edge := range s.adjacent(s.out.m[id]) {
+			to := edge.Other(id)
+			if alt := d + edge.Weight; alt > tree.Distances[to] {
+				tree.Distances[to] = alt
+				tree.Predecessors[to] = edge
+			}
+		}
+	}
+
+	start := end
+	for tree.Predecessors[start] != nil {
+		start = tree.Predecessors[start].From
+	}
+	tree.Source = start
+	return tree.PathTo(end)
+}
+
+// kahn runs Kahn's algorithm, returning the nodes in waves, or a *CycleError
+// if some nodes are left over because they are on or behind a cycle.
+func (s *store) kahn(search *search) ([][]string, error) {
+	indegree := make(map[string]int, len(s.nodes.m))
+	var wave []string
+	for id := range s.nodes.m {
+		indegree[id] = len(s.in.m[id].m)
+		if indegree[id] == 0 {
+			wave = append(wave, id)
+		}
+	}
+
+	var waves [][]string
+	ordered := 0
+	for len(wave) > 0 {
+		sort.Strings(wave)
+		waves = append(waves, wave)
+		var next []string
+		for _, id := range wave {
+			if err := search.visit(); err != nil {
+				return nil, err
+			}
+			ordered++
+			for _, edge := range s.out.m[id].m {
+				to := edge.Other(id)
+				indegree[to]--
+				if indegree[to] == 0 {
+					next = append(next, to)
+				}
+			}
+		}
+		wave = next
+	}
+
+	if ordered < len(s.nodes.m) {
+		return nil, &CycleError{Cycle: s.leftoverCycle(indegree)}
+	}
+	return waves, nil
+}
+
+// leftoverCycle finds a cycle among the nodes Kahn's algorithm could not
+// order. Each of them has an incoming edge from another, so walking those
+// edges backwards must come round to a node already seen.
+func (s *store) leftoverCycle(indegree map[string]int) *Path {
+	var start string
+	for id, d := range indegree {
+		if d > 0 && (start == "" || id < start) {
+			start = id
+		}
+	}
+
+	seen := make(map[string]int)
+	var nodes []string
+	var edges []*Edge
+	current := start
+	for {
+		if i, ok := seen[current]; ok {
+			nodes, edges = nodes[i:], edges[i:]
+			break
+		}
+		seen[current] = len(nodes)
+		nodes = append(nodes, current)
+		for _, edge := range s.adjacent(s.in.m[current]) {
+			if indegree[edge.Other(current)] > 0 {
+				edges = append(edges, edge)
+				current = edge.Other(current)
+				break
+			}
+		}
+	}
+
+	// The walk went against the edges; turn it around
+	cycle := &Path{Nodes: []string{nodes[0]}}
+	for i := len(edges) - 1; i >= 0; i-- {
+		cycle.Nodes = append(cycle.Nodes, nodes[i])
+		cycle.Edges = append(cycle.Edges, edges[i].clone())
+		cycle.Weight += edges[i].Weight
+	}
+	return cycle
+}
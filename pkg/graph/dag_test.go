@@ -0,0 +1,191 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// newBuildGraph builds a graph of build steps weighted by duration:
+//
+//	fetch --2--> compile --5--> test --1--> release
+//	fetch --1--> lint --1--> release
+//	docs --4--> release
+func newBuildGraph(t *testing.T) Graph {
+	t.Helper()
+	return buildGraph(t, nodesWithIDs("fetch", "compile", "test", "lint", "docs", "release"), []*Edge{
+		{ID: "e1", From: "fetch", To: "compile", Weight: 2.0},
+		{ID: "e2", From: "compile", To: "test", Weight: 5.0},
+		{ID: "e3", From: "test", To: "release", Weight: 1.0},
+		{ID: "e4", From: "fetch", To: "lint", Weight: 1.0},
+		{ID: "e5", From: "lint", To: "release", Weight: 1.0},
+		{ID: "e6", From: "docs", To: "release", Weight: 4.0},
+	})
+}
+
+// TestTopologicalSort tests that every edge leads forwards in the order
+func TestTopologicalSort(t *testing.T) {
+	g := newBuildGraph(t)
+
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	want := []string{"docs", "fetch", "compile", "lint", "test", "release"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+
+	position := make(map[string]int, len(order))
+	for i, id := range order {
+		position[id] = i
+	}
+	for _, edge := range g.AllEdges() {
+		if position[edge.From] >= position[edge.To] {
+			t.Errorf("Expected %s before %s", edge.From, edge.To)
+		}
+	}
+	if !g.IsDAG() {
+		t.Errorf("Expected the build graph to be a DAG")
+	}
+
+	empty, err := NewGraph().TopologicalSort()
+	if err != nil || len(empty) != 0 {
+		t.Errorf("Expected an empty order for an empty graph, got %v: %v", empty, err)
+	}
+}
+
+// TestTopologicalWaves tests grouping nodes into waves that can run in parallel
+func TestTopologicalWaves(t *testing.T) {
+	g := newBuildGraph(t)
+
+	waves, err := g.TopologicalWaves()
+	if err != nil {
+		t.Fatalf("TopologicalWaves failed: %v", err)
+	}
+	want := [][]string{{"docs", "fetch"}, {"compile", "lint"}, {"test"}, {"release"}}
+	if !reflect.DeepEqual(waves, want) {
+		t.Errorf("Expected waves %v, got %v", want, waves)
+	}
+}
+
+// TestTopologicalSortCycle tests that a cycle is reported as an error
+func TestTopologicalSortCycle(t *testing.T) {
+	g := newBuildGraph(t)
+	if err := g.AddEdge(&Edge{ID: "e7", From: "test", To: "compile", Weight: 3.0}); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+
+	_, err := g.TopologicalSort()
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a cycle error, got %v", err)
+	}
+	cycle := cycleErr.Cycle
+	if want := []string{"compile", "test", "compile"}; !reflect.DeepEqual(cycle.Nodes, want) {
+		t.Errorf("Expected cycle %v, got %v", want, cycle.Nodes)
+	}
+	if want := []string{"e2", "e7"}; !reflect.DeepEqual(edgeIDs(cycle.Edges), want) {
+		t.Errorf("Expected cycle edges %v, got %v", want, edgeIDs(cycle.Edges))
+	}
+	if cycle.Weight != 8 {
+		t.Errorf("Expected cycle weight 8, got %f", cycle.Weight)
+	}
+
+	if g.IsDAG() {
+		t.Errorf("Expected a graph with a cycle not to be a DAG")
+	}
+	if _, err := g.TopologicalWaves(); !errors.As(err, &cycleErr) {
+		t.Errorf("Expected TopologicalWaves to report the cycle, got %v", err)
+	}
+	if _, err := g.LongestPath(); !errors.As(err, &cycleErr) {
+		t.Errorf("Expected LongestPath to report the cycle, got %v", err)
+	}
+}
+
+// TestTopologicalSortLoops tests that self-loops and undirected edges count as cycles
+func TestTopologicalSortLoops(t *testing.T) {
+	g := newChain(t, 3)
+	if err := g.AddEdge(&Edge{ID: "loop", From: "n1", To: "n1", Weight: 1.0}); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+	_, err := g.TopologicalSort()
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a cycle error, got %v", err)
+	}
+	if want := []string{"n1", "n1"}; !reflect.DeepEqual(cycleErr.Cycle.Nodes, want) {
+		t.Errorf("Expected cycle %v, got %v", want, cycleErr.Cycle.Nodes)
+	}
+
+	u := newChain(t, 3, WithDirectedness(UndirectedGraph))
+	if u.IsDAG() {
+		t.Errorf("Expected an undirected graph with edges not to be a DAG")
+	}
+}
+
+// TestLongestPath tests finding the critical path of a DAG
+func TestLongestPath(t *testing.T) {
+	g := newBuildGraph(t)
+
+	path, err := g.LongestPath()
+	if err != nil {
+		t.Fatalf("LongestPath failed: %v", err)
+	}
+	if want := []string{"fetch", "compile", "test", "release"}; !reflect.DeepEqual(path.Nodes, want) {
+		t.Errorf("Expected critical path %v, got %v", want, path.Nodes)
+	}
+	if want := []string{"e1", "e2", "e3"}; !reflect.DeepEqual(edgeIDs(path.Edges), want) {
+		t.Errorf("Expected critical path edges %v, got %v", want, edgeIDs(path.Edges))
+	}
+	if path.Weight != 8 {
+		t.Errorf("Expected critical path weight 8, got %f", path.Weight)
+	}
+
+	// Changing the path handed out leaves the graph alone
+	path.Edges[0].Weight = 100
+	if edge, _ := g.GetEdgeByID("e1"); edge.Weight != 2 {
+		t.Errorf("Expected the graph's edge to be unchanged, got weight %f", edge.Weight)
+	}
+
+	single := NewGraph()
+	if err := single.AddNode(&Node{ID: "A"}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	path, err = single.LongestPath()
+	if err != nil || !reflect.DeepEqual(path.Nodes, []string{"A"}) || path.Weight != 0 {
+		t.Errorf("Expected a single node path, got %v: %v", path, err)
+	}
+	if _, err := NewGraph().LongestPath(); err == nil {
+		t.Errorf("Expected error for an empty graph")
+	}
+}
+
+// TestTopologicalSortLimits tests that the sort respects cancellation and the visit limit
+func TestTopologicalSortLimits(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := newChain(t, 10).TopologicalSortContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected TopologicalSort to be cancelled, got %v", err)
+	}
+	if _, err := newChain(t, 10, WithVisitLimit(5)).TopologicalSort(); !errors.Is(err, ErrVisitLimit) {
+		t.Errorf("Expected TopologicalSort to hit the visit limit, got %v", err)
+	}
+
+	// IsDAG cannot report the limit, so it is not bound by it
+	g := newChain(t, 10, WithVisitLimit(5))
+	if !g.IsDAG() {
+		t.Errorf("Expected IsDAG to ignore the visit limit")
+	}
+	if _, err := g.IsDAGContext(context.Background()); !errors.Is(err, ErrVisitLimit) {
+		t.Errorf("Expected IsDAGContext to hit the visit limit, got %v", err)
+	}
+	g = buildGraph(t, nodesWithIDs("A", "B"), []*Edge{
+		{ID: "e1", From: "A", To: "B"},
+		{ID: "e2", From: "B", To: "A"},
+	})
+	if dag, err := g.IsDAGContext(context.Background()); dag || err != nil {
+		t.Errorf("Expected a graph with a cycle not to be a DAG, got %v (%v)", dag, err)
+	}
+}
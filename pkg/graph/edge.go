@@ -1,8 +1,57 @@
 package graph
 
+import "maps"
+
+// Edge connects two nodes. Directed edges run from From to To; undirected
+// edges can be traversed either way. The graph's Directedness decides which an
+// edge is, and in a MixedGraph each edge decides through Directed. Once an
+// edge is stored, Directed always reflects how it is treated.
 type Edge struct {
 	ID         string
 	From, To   string
+	Directed   bool
+	Type       string
 	Weight     float64
 	Properties map[string]any
+
+	// seq is the edge's position in the graph's insertion order.
+	seq uint64
+}
+
+// Other returns the endpoint of the edge opposite to the given node. For an
+// edge leaving id, in either direction, it is the node the edge leads to.
+func (e *Edge) Other(id string) string {
+	if id == e.From {
+		return e.To
+	}
+	return e.From
+}
+
+// hasType reports whether the edge matches one of the given types. An empty
+// list matches every edge.
+func (e *Edge) hasType(types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if e.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a copy of the edge that shares no properties with it.
+// Property values themselves are not copied.
+func (e *Edge) clone() *Edge {
+	c := *e
+	c.Properties = maps.Clone(e.Properties)
+	return &c
+}
+
+func cloneEdges(edges []*Edge) []*Edge {
+	for i, edge := range edges {
+		edges[i] = edge.clone()
+	}
+	return edges
 }
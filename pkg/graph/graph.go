@@ -1,299 +1,799 @@
 package graph
 
 import (
-	"container/heap"
+	"context"
+	"errors"
 	"fmt"
-	"math"
+	"iter"
 	"sync"
-
-	pq "github.com/camwhite18/ArachneGraph/pkg/priority_queue"
 )
 
-type Graph interface {
+// Reader is the read-only half of a graph.
+type Reader interface {
+	// Deprecated: Use AllNodes.
 	Nodes() map[string]*Node
+	// Deprecated: Use OutEdgesByID or AllEdges.
 	OutEdges() map[string]map[string]*Edge
-	AddNode(node *Node) error
+	OutEdgesByID() map[string]map[string]*Edge
+	Config() Config
+	AllNodes() iter.Seq2[string, *Node]
+	AllEdges() iter.Seq2[string, *Edge]
+	NodeCount() int
+	EdgeCount() int
 	GetNode(id string) (*Node, error)
-	DeleteNode(id string) error
-	AddEdge(edge *Edge) error
 	GetEdge(from, to string) (*Edge, error)
-	DeleteEdge(from, to string) error
-	Neighbors(id string) ([]*Edge, error)
-	Incoming(id string) ([]*Edge, error)
+	GetEdgeByID(id string) (*Edge, error)
+	EdgesBetween(from, to string) ([]*Edge, error)
+	Neighbors(id string, types ...string) ([]*Edge, error)
+	Incoming(id string, types ...string) ([]*Edge, error)
+	NodesByLabel(label string) []*Node
+	EdgesByType(edgeType string) []*Edge
+	Indexes() []IndexDefinition
+	FindNodes(label, property string, value any) ([]*Node, error)
+	FindNodesInRange(label, property string, lo, hi any) ([]*Node, error)
+	FindNodesWithPrefix(label, property, prefix string) ([]*Node, error)
+	Constraints() []Constraint
 	DFS(start string) ([]string, error)
+	DFSContext(ctx context.Context, start string) ([]string, error)
 	BFS(start string) ([]string, error)
+	BFSContext(ctx context.Context, start string) ([]string, error)
 	ShortestPath(source, target string) ([]string, float64, error)
+	ShortestPathContext(ctx context.Context, source, target string) ([]string, float64, error)
+	AStar(source, target string, heuristic func(a, b *Node) float64) ([]string, float64, error)
+	AStarContext(ctx context.Context, source, target string, heuristic func(a, b *Node) float64) ([]string, float64, error)
+	BellmanFord(source string) (*PathTree, error)
+	BellmanFordContext(ctx context.Context, source string) (*PathTree, error)
+	AllPairsShortestPaths(alg AllPairsAlgorithm) (*DistanceMatrix, error)
+	AllPairsShortestPathsContext(ctx context.Context, alg AllPairsAlgorithm) (*DistanceMatrix, error)
+	ShortestPathTree(source string) (*PathTree, error)
+	ShortestPathTreeContext(ctx context.Context, source string) (*PathTree, error)
+	KShortestPaths(source, target string, k int) ([]*Path, error)
+	KShortestPathsContext(ctx context.Context, source, target string, k int) ([]*Path, error)
+	BidirectionalShortestPath(source, target string) ([]string, float64, error)
+	BidirectionalShortestPathContext(ctx context.Context, source, target string) ([]string, float64, error)
+	TopologicalSort() ([]string, error)
+	TopologicalSortContext(ctx context.Context) ([]string, error)
+	TopologicalWaves() ([][]string, error)
+	TopologicalWavesContext(ctx context.Context) ([][]string, error)
+	IsDAG() bool
+	IsDAGContext(ctx context.Context) (bool, error)
+	LongestPath() (*Path, error)
+	LongestPathContext(ctx context.Context) (*Path, error)
+	FindCycle() (*Path, error)
+	FindCycleContext(ctx context.Context) (*Path, error)
+	HasCycle() bool
+	HasCycleContext(ctx context.Context) (bool, error)
+	SimpleCycles(limit int) ([]*Path, error)
+	SimpleCyclesContext(ctx context.Context, limit int) ([]*Path, error)
+	StronglyConnectedComponents() (*Components, error)
+	StronglyConnectedComponentsContext(ctx context.Context) (*Components, error)
+	Condensation() (Graph, error)
+	CondensationContext(ctx context.Context) (Graph, error)
+	WeaklyConnectedComponents() (*Components, error)
+	WeaklyConnectedComponentsContext(ctx context.Context) (*Components, error)
+	SameComponent(a, b string) (bool, error)
+	ComponentCount() int
+	ComponentCountContext(ctx context.Context) (int, error)
+	MinimumSpanningTree(alg SpanningAlgorithm) (*SpanningTree, error)
+	MinimumSpanningTreeContext(ctx context.Context, alg SpanningAlgorithm) (*SpanningTree, error)
+	MinimumSpanningArborescence(root string) (*SpanningTree, error)
+	MinimumSpanningArborescenceContext(ctx context.Context, root string) (*SpanningTree, error)
+	Traverse(start string, opts TraversalOptions) ([]Visit, error)
+	TraverseContext(ctx context.Context, start string, opts TraversalOptions) ([]Visit, error)
+}
+
+// Writer is the mutating half of a graph. Nodes and edges are copied on the
+// way in and on the way out, so the only way to change a stored element is
+// through a Writer method. UpdateNode and UpdateEdge merge a patch into the
+// element's properties, removing the properties whose patch value is nil.
+type Writer interface {
+	AddNode(node *Node) error
+	UpdateNode(id string, patch map[string]any) (*Node, error)
+	SetNodeProperty(id, key string, value any) error
+	RemoveNodeProperty(id, key string) error
+	DeleteNode(id string) error
+	AddEdge(edge *Edge) error
+	UpdateEdge(id string, patch map[string]any) (*Edge, error)
+	SetEdgeWeight(id string, weight float64) error
+	DeleteEdge(from, to string) error
+	DeleteEdgeByID(id string) error
+	CreateIndex(label, property string, kind IndexKind) error
+	DropIndex(label, property string) error
+	CreateConstraint(c Constraint) error
+	DropConstraint(c Constraint) error
+}
+
+type Graph interface {
+	Reader
+	Writer
+	Begin() Tx
+	Update(fn func(tx Tx) error) error
+	Snapshot() Reader
+	Save() error
 }
 
+// graphImpl guards a store with a read-write mutex. Every method takes the
+// lock for its own duration, except Begin which hands the write lock to the
+// returned transaction.
 type graphImpl struct {
-	nodes map[string]*Node
-	out   map[string]map[string]*Edge
-	in    map[string]map[string]*Edge
-	mu    sync.RWMutex
+	s  *store
+	mu sync.RWMutex
 }
 
-func NewGraph() Graph {
+// New creates an empty graph configured by opts. Without options the graph is
+// a directed multigraph that allows self-loops. It fails if the options
+// cannot be used together.
+func New(opts ...Option) (Graph, error) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid graph config: %v", err)
+	}
 	return &graphImpl{
-		nodes: make(map[string]*Node),
-		out:   make(map[string]map[string]*Edge),
-		in:    make(map[string]map[string]*Edge),
-		mu:    sync.RWMutex{},
+		s:  newStore(cfg),
+		mu: sync.RWMutex{},
+	}, nil
+}
+
+// NewGraph is like New but panics if the options cannot be used together.
+func NewGraph(opts ...Option) Graph {
+	g, err := New(opts...)
+	if err != nil {
+		panic(err)
 	}
+	return g
+}
+
+// Begin starts a transaction. The transaction holds the graph's write lock
+// until it is committed or rolled back, so other readers and writers block
+// and never observe its uncommitted changes. The graph's own methods must not
+// be called from the goroutine running the transaction; use the methods of
+// the returned Tx instead.
+func (g *graphImpl) Begin() Tx {
+	g.mu.Lock()
+	return &txImpl{store: g.s, g: g}
+}
+
+// Update runs fn in a transaction, committing it if fn returns nil and rolling
+// it back if fn returns an error or panics.
+func (g *graphImpl) Update(fn func(tx Tx) error) error {
+	tx := g.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// Snapshot returns an immutable, point-in-time view of the graph. Reads on the
+// snapshot take no locks and never block, or are blocked by, writers, so long
+// running algorithms should be run against a snapshot. Taking a snapshot is
+// O(1); the cost is paid by the graph's next writes, each of which copies the
+// internal maps it touches the first time it touches them after a snapshot.
+// Like every other method, Snapshot waits for an open transaction to finish.
+//
+// Like the graph, a snapshot returns copies of its nodes and edges, which the
+// caller may modify freely. Callbacks that are passed the stored nodes and
+// edges instead say so where they are declared.
+func (g *graphImpl) Snapshot() Reader {
+	return g.snapshot()
+}
+
+// Save writes the graph through the persister configured with WithPersister.
+func (g *graphImpl) Save() error {
+	p := g.s.cfg.Persister
+	if p == nil {
+		return fmt.Errorf("graph has no persister")
+	}
+	return p.Save(g)
+}
+
+func (g *graphImpl) snapshot() *store {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.freeze()
 }
 
 func (g *graphImpl) Nodes() map[string]*Node {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	return g.nodes
+	return g.s.Nodes()
 }
 
 func (g *graphImpl) OutEdges() map[string]map[string]*Edge {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	return g.out
+	return g.s.OutEdges()
+}
+
+func (g *graphImpl) OutEdgesByID() map[string]map[string]*Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.OutEdgesByID()
+}
+
+// AllNodes iterates over the nodes as they were when iteration starts, so
+// the loop body may freely read and write the graph. Starting takes the read
+// lock just long enough to list the nodes; unlike Snapshot, it leaves the
+// graph's next writes no maps to copy.
+func (g *graphImpl) AllNodes() iter.Seq2[string, *Node] {
+	return func(yield func(string, *Node) bool) {
+		g.mu.RLock()
+		nodes := g.s.nodeList()
+		g.mu.RUnlock()
+
+		for _, node := range nodes {
+			if !yield(node.ID, node.clone()) {
+				return
+			}
+		}
+	}
+}
+
+// AllEdges iterates over the edges as they were when iteration starts, like
+// AllNodes.
+func (g *graphImpl) AllEdges() iter.Seq2[string, *Edge] {
+	return func(yield func(string, *Edge) bool) {
+		g.mu.RLock()
+		edges := g.s.edgeList()
+		g.mu.RUnlock()
+
+		for _, edge := range edges {
+			if !yield(edge.ID, edge.clone()) {
+				return
+			}
+		}
+	}
+}
+
+// Config returns the configuration the graph was created with. It never
+// changes, so no lock is needed.
+func (g *graphImpl) Config() Config {
+	return g.s.Config()
+}
+
+func (g *graphImpl) NodeCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.NodeCount()
+}
+
+func (g *graphImpl) EdgeCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.EdgeCount()
 }
 
 func (g *graphImpl) AddNode(node *Node) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if _, exists := g.nodes[node.ID]; exists {
-		return fmt.Errorf("node %q already exists", node.ID)
-	}
-	g.nodes[node.ID] = node
-	return nil
+	return g.s.addNode(node)
+}
+
+func (g *graphImpl) UpdateNode(id string, patch map[string]any) (*Node, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.updateNode(id, patch)
+}
+
+func (g *graphImpl) SetNodeProperty(id, key string, value any) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.setNodeProperty(id, key, value)
+}
+
+func (g *graphImpl) RemoveNodeProperty(id, key string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.removeNodeProperty(id, key)
 }
 
 func (g *graphImpl) GetNode(id string) (*Node, error) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	node, exists := g.nodes[id]
-	if !exists {
-		return nil, fmt.Errorf("node %q not found", id)
-	}
-	return node, nil
+	return g.s.GetNode(id)
 }
 
 func (g *graphImpl) DeleteNode(id string) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if _, exists := g.nodes[id]; !exists {
-		return fmt.Errorf("node %q not found", id)
-	}
-	delete(g.nodes, id)
-	delete(g.out, id)
-	delete(g.in, id)
-	for from := range g.out {
-		delete(g.out[from], id)
-	}
-	for to := range g.in {
-		delete(g.in[to], id)
-	}
-	return nil
+	return g.s.deleteNode(id)
 }
 
 func (g *graphImpl) AddEdge(edge *Edge) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if _, exists := g.nodes[edge.From]; !exists {
-		return fmt.Errorf("node %q not found", edge.From)
-	}
-	if _, exists := g.nodes[edge.To]; !exists {
-		return fmt.Errorf("node %q not found", edge.To)
-	}
-	if g.out[edge.From] == nil {
-		g.out[edge.From] = make(map[string]*Edge)
-	}
-	if g.in[edge.To] == nil {
-		g.in[edge.To] = make(map[string]*Edge)
-	}
-	if _, exists := g.out[edge.From][edge.To]; exists {
-		return fmt.Errorf("edge from %q to %q already exists", edge.From, edge.To)
-	}
-	g.out[edge.From][edge.To] = edge
-	g.in[edge.To][edge.From] = edge
-	return nil
+	return g.s.addEdge(edge)
+}
+
+func (g *graphImpl) UpdateEdge(id string, patch map[string]any) (*Edge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.updateEdge(id, patch)
+}
+
+func (g *graphImpl) SetEdgeWeight(id string, weight float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.setEdgeWeight(id, weight)
 }
 
 func (g *graphImpl) GetEdge(from, to string) (*Edge, error) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	edge, exists := g.out[from][to]
-	if !exists {
-		return nil, fmt.Errorf("edge from %q to %q not found", from, to)
-	}
-	return edge, nil
+	return g.s.GetEdge(from, to)
+}
+
+func (g *graphImpl) GetEdgeByID(id string) (*Edge, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.GetEdgeByID(id)
+}
+
+func (g *graphImpl) EdgesBetween(from, to string) ([]*Edge, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.EdgesBetween(from, to)
 }
 
 func (g *graphImpl) DeleteEdge(from, to string) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if _, exists := g.out[from][to]; !exists {
-		return fmt.Errorf("edge from %q to %q not found", from, to)
-	}
-	delete(g.out[from], to)
-	delete(g.in[to], from)
-	return nil
+	return g.s.deleteEdge(from, to)
 }
 
-func (g *graphImpl) Neighbors(id string) ([]*Edge, error) {
+func (g *graphImpl) DeleteEdgeByID(id string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.deleteEdgeByID(id)
+}
+
+func (g *graphImpl) Neighbors(id string, types ...string) ([]*Edge, error) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	if _, exists := g.nodes[id]; !exists {
-		return nil, fmt.Errorf("node %q not found", id)
-	}
-	edges := make([]*Edge, 0, len(g.out[id]))
-	for _, edge := range g.out[id] {
-		edges = append(edges, edge)
-	}
-	return edges, nil
+	return g.s.Neighbors(id, types...)
 }
 
-func (g *graphImpl) Incoming(id string) ([]*Edge, error) {
+func (g *graphImpl) Incoming(id string, types ...string) ([]*Edge, error) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	if _, exists := g.nodes[id]; !exists {
-		return nil, fmt.Errorf("node %q not found", id)
-	}
-	edges := make([]*Edge, 0, len(g.in[id]))
-	for _, edge := range g.in[id] {
-		edges = append(edges, edge)
-	}
-	return edges, nil
+	return g.s.Incoming(id, types...)
 }
 
-// DFS performs depth-first search starting from the given node
-func (g *graphImpl) DFS(start string) ([]string, error) {
+func (g *graphImpl) NodesByLabel(label string) []*Node {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	if _, exists := g.nodes[start]; !exists {
-		return nil, fmt.Errorf("node %q not found", start)
-	}
+	return g.s.NodesByLabel(label)
+}
 
-	visited := make(map[string]bool)
-	var result []string
+func (g *graphImpl) EdgesByType(edgeType string) []*Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-	var dfs func(id string)
-	dfs = func(id string) {
-		if visited[id] {
-			return
-		}
-		visited[id] = true
-		result = append(result, id)
+	return g.s.EdgesByType(edgeType)
+}
 
-		// Visit neighbors in consistent order (sorted by target ID)
-		for _, edge := range g.out[id] {
-			dfs(edge.To)
-		}
-	}
+func (g *graphImpl) CreateIndex(label, property string, kind IndexKind) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	dfs(start)
-	return result, nil
+	return g.s.createIndex(label, property, kind)
 }
 
-// BFS performs breadth-first search starting from the given node
-func (g *graphImpl) BFS(start string) ([]string, error) {
+func (g *graphImpl) DropIndex(label, property string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.dropIndex(label, property)
+}
+
+func (g *graphImpl) Indexes() []IndexDefinition {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	if _, exists := g.nodes[start]; !exists {
-		return nil, fmt.Errorf("node %q not found", start)
-	}
+	return g.s.Indexes()
+}
 
-	visited := make(map[string]bool)
-	var result []string
-	queue := []string{start}
-	visited[start] = true
+func (g *graphImpl) FindNodes(label, property string, value any) ([]*Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-		result = append(result, current)
+	return g.s.FindNodes(label, property, value)
+}
 
-		for _, edge := range g.out[current] {
-			if !visited[edge.To] {
-				visited[edge.To] = true
-				queue = append(queue, edge.To)
-			}
-		}
-	}
+func (g *graphImpl) FindNodesInRange(label, property string, lo, hi any) ([]*Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.FindNodesInRange(label, property, lo, hi)
+}
+
+func (g *graphImpl) FindNodesWithPrefix(label, property, prefix string) ([]*Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.FindNodesWithPrefix(label, property, prefix)
+}
+
+func (g *graphImpl) CreateConstraint(c Constraint) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.createConstraint(c)
+}
+
+func (g *graphImpl) DropConstraint(c Constraint) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.s.dropConstraint(c)
+}
+
+func (g *graphImpl) Constraints() []Constraint {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.Constraints()
+}
+
+func (g *graphImpl) DFS(start string) ([]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.DFS(start)
+}
+
+func (g *graphImpl) DFSContext(ctx context.Context, start string) ([]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.DFSContext(ctx, start)
+}
+
+func (g *graphImpl) BFS(start string) ([]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.BFS(start)
+}
+
+func (g *graphImpl) BFSContext(ctx context.Context, start string) ([]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-	return result, nil
+	return g.s.BFSContext(ctx, start)
 }
 
-// ShortestPath computes the shortest path using Dijkstra's algorithm
 func (g *graphImpl) ShortestPath(source, target string) ([]string, float64, error) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	if _, exists := g.nodes[source]; !exists {
-		return nil, 0, fmt.Errorf("source node %q not found", source)
-	}
-	if _, exists := g.nodes[target]; !exists {
-		return nil, 0, fmt.Errorf("target node %q not found", target)
-	}
+	return g.s.ShortestPath(source, target)
+}
 
-	// Initialise distances and previous nodes
-	dist := make(map[string]float64)
-	prev := make(map[string]string)
-	for id := range g.nodes {
-		dist[id] = math.Inf(1)
-	}
-	dist[source] = 0
+func (g *graphImpl) ShortestPathContext(ctx context.Context, source, target string) ([]string, float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-	// Priority queue for Dijkstra's
-	priorityQueue := make(pq.PriorityQueue, 0)
-	heap.Init(&priorityQueue)
-	heap.Push(&priorityQueue, &pq.PriorityQueueItem{NodeID: source, Distance: 0})
+	return g.s.ShortestPathContext(ctx, source, target)
+}
 
-	visited := make(map[string]bool)
+func (g *graphImpl) Traverse(start string, opts TraversalOptions) ([]Visit, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-	for priorityQueue.Len() > 0 {
-		item := heap.Pop(&priorityQueue).(*pq.PriorityQueueItem)
-		current := item.NodeID
+	return g.s.Traverse(start, opts)
+}
 
-		if visited[current] {
-			continue
-		}
-		visited[current] = true
-
-		// If we reached the target, reconstruct path
-		if current == target {
-			var path []string
-			for node := target; node != ""; node = prev[node] {
-				path = append([]string{node}, path...)
-				if node == source {
-					break
-				}
-			}
-			return path, dist[target], nil
-		}
+func (g *graphImpl) TraverseContext(ctx context.Context, start string, opts TraversalOptions) ([]Visit, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-		// Check neighbours
-		for _, edge := range g.out[current] {
-			neighbor := edge.To
-			if visited[neighbor] {
-				continue
-			}
+	return g.s.TraverseContext(ctx, start, opts)
+}
 
-			alt := dist[current] + edge.Weight
-			if alt < dist[neighbor] {
-				dist[neighbor] = alt
-				prev[neighbor] = current
-				heap.Push(&priorityQueue, &pq.PriorityQueueItem{NodeID: neighbor, Distance: alt})
-			}
-		}
-	}
+func (g *graphImpl) AStar(source, target string, heuristic func(a, b *Node) float64) ([]string, float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.AStar(source, target, heuristic)
+}
+
+func (g *graphImpl) AStarContext(ctx context.Context, source, target string, heuristic func(a, b *Node) float64) ([]string, float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.AStarContext(ctx, source, target, heuristic)
+}
+
+func (g *graphImpl) BellmanFord(source string) (*PathTree, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.BellmanFord(source)
+}
+
+func (g *graphImpl) BellmanFordContext(ctx context.Context, source string) (*PathTree, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.BellmanFordContext(ctx, source)
+}
+
+func (g *graphImpl) AllPairsShortestPaths(alg AllPairsAlgorithm) (*DistanceMatrix, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.AllPairsShortestPaths(alg)
+}
+
+func (g *graphImpl) AllPairsShortestPathsContext(ctx context.Context, alg AllPairsAlgorithm) (*DistanceMatrix, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.AllPairsShortestPathsContext(ctx, alg)
+}
+
+func (g *graphImpl) ShortestPathTree(source string) (*PathTree, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.ShortestPathTree(source)
+}
+
+func (g *graphImpl) ShortestPathTreeContext(ctx context.Context, source string) (*PathTree, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.ShortestPathTreeContext(ctx, source)
+}
+
+func (g *graphImpl) KShortestPaths(source, target string, k int) ([]*Path, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.KShortestPaths(source, target, k)
+}
+
+func (g *graphImpl) KShortestPathsContext(ctx context.Context, source, target string, k int) ([]*Path, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.KShortestPathsContext(ctx, source, target, k)
+}
+
+func (g *graphImpl) BidirectionalShortestPath(source, target string) ([]string, float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.BidirectionalShortestPath(source, target)
+}
+
+func (g *graphImpl) BidirectionalShortestPathContext(ctx context.Context, source, target string) ([]string, float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.BidirectionalShortestPathContext(ctx, source, target)
+}
+
+func (g *graphImpl) TopologicalSort() ([]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.TopologicalSort()
+}
+
+func (g *graphImpl) TopologicalSortContext(ctx context.Context) ([]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.TopologicalSortContext(ctx)
+}
+
+func (g *graphImpl) TopologicalWaves() ([][]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.TopologicalWaves()
+}
+
+func (g *graphImpl) TopologicalWavesContext(ctx context.Context) ([][]string, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.TopologicalWavesContext(ctx)
+}
+
+func (g *graphImpl) IsDAG() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.IsDAG()
+}
+
+func (g *graphImpl) IsDAGContext(ctx context.Context) (bool, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.IsDAGContext(ctx)
+}
+
+func (g *graphImpl) LongestPath() (*Path, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.LongestPath()
+}
+
+func (g *graphImpl) LongestPathContext(ctx context.Context) (*Path, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.LongestPathContext(ctx)
+}
+
+func (g *graphImpl) FindCycle() (*Path, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.FindCycle()
+}
+
+func (g *graphImpl) FindCycleContext(ctx context.Context) (*Path, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.FindCycleContext(ctx)
+}
+
+func (g *graphImpl) HasCycle() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.HasCycle()
+}
+
+func (g *graphImpl) HasCycleContext(ctx context.Context) (bool, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.HasCycleContext(ctx)
+}
+
+func (g *graphImpl) SimpleCycles(limit int) ([]*Path, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.SimpleCycles(limit)
+}
+
+func (g *graphImpl) SimpleCyclesContext(ctx context.Context, limit int) ([]*Path, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.SimpleCyclesContext(ctx, limit)
+}
+
+func (g *graphImpl) StronglyConnectedComponents() (*Components, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.StronglyConnectedComponents()
+}
+
+func (g *graphImpl) StronglyConnectedComponentsContext(ctx context.Context) (*Components, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.StronglyConnectedComponentsContext(ctx)
+}
+
+func (g *graphImpl) Condensation() (Graph, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.Condensation()
+}
+
+func (g *graphImpl) CondensationContext(ctx context.Context) (Graph, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.CondensationContext(ctx)
+}
+
+func (g *graphImpl) WeaklyConnectedComponents() (*Components, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.WeaklyConnectedComponents()
+}
+
+func (g *graphImpl) WeaklyConnectedComponentsContext(ctx context.Context) (*Components, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.WeaklyConnectedComponentsContext(ctx)
+}
+
+func (g *graphImpl) SameComponent(a, b string) (bool, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.SameComponent(a, b)
+}
+
+func (g *graphImpl) ComponentCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.ComponentCount()
+}
+
+func (g *graphImpl) ComponentCountContext(ctx context.Context) (int, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.ComponentCountContext(ctx)
+}
+
+func (g *graphImpl) MinimumSpanningTree(alg SpanningAlgorithm) (*SpanningTree, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.MinimumSpanningTree(alg)
+}
+
+func (g *graphImpl) MinimumSpanningTreeContext(ctx context.Context, alg SpanningAlgorithm) (*SpanningTree, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.MinimumSpanningTreeContext(ctx, alg)
+}
+
+func (g *graphImpl) MinimumSpanningArborescence(root string) (*SpanningTree, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.s.MinimumSpanningArborescence(root)
+}
+
+func (g *graphImpl) MinimumSpanningArborescenceContext(ctx context.Context, root string) (*SpanningTree, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-	return nil, 0, fmt.Errorf("no path found from %q to %q", source, target)
+	return g.s.MinimumSpanningArborescenceContext(ctx, root)
 }
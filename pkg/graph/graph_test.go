@@ -143,14 +143,16 @@ func TestAddEdgeToNonExistentNode(t *testing.T) {
 	}
 }
 
-// TestAddDuplicateEdge tests adding duplicate edges
+// TestAddDuplicateEdge tests adding edges with a duplicate ID
 func TestAddDuplicateEdge(t *testing.T) {
 	g := NewGraph()
 
 	nodeA := &Node{ID: "A"}
 	nodeB := &Node{ID: "B"}
+	nodeC := &Node{ID: "C"}
 	g.AddNode(nodeA)
 	g.AddNode(nodeB)
+	g.AddNode(nodeC)
 
 	edge1 := &Edge{ID: "e1", From: "A", To: "B", Weight: 1.0}
 	err := g.AddEdge(edge1)
@@ -158,13 +160,138 @@ func TestAddDuplicateEdge(t *testing.T) {
 		t.Fatalf("Failed to add first edge: %v", err)
 	}
 
-	edge2 := &Edge{ID: "e2", From: "A", To: "B", Weight: 2.0}
+	edge2 := &Edge{ID: "e1", From: "B", To: "C", Weight: 2.0}
 	err = g.AddEdge(edge2)
 	if err == nil {
 		t.Error("Expected error when adding duplicate edge, got nil")
 	}
 }
 
+// TestAddEdgeWithoutID tests that edges must carry an ID
+func TestAddEdgeWithoutID(t *testing.T) {
+	g := NewGraph()
+
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+
+	err := g.AddEdge(&Edge{From: "A", To: "B", Weight: 1.0})
+	if err == nil {
+		t.Error("Expected error when adding edge without ID, got nil")
+	}
+}
+
+// TestParallelEdges tests adding and addressing parallel edges
+func TestParallelEdges(t *testing.T) {
+	g := NewGraph()
+
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+
+	if err := g.AddEdge(&Edge{ID: "e2", From: "A", To: "B", Weight: 2.0}); err != nil {
+		t.Fatalf("Failed to add first edge: %v", err)
+	}
+	if err := g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 1.0}); err != nil {
+		t.Fatalf("Failed to add parallel edge: %v", err)
+	}
+
+	between, err := g.EdgesBetween("A", "B")
+	if err != nil {
+		t.Fatalf("Failed to get edges between nodes: %v", err)
+	}
+	if len(between) != 2 || between[0].ID != "e1" || between[1].ID != "e2" {
+		t.Errorf("Expected edges [e1 e2], got %v", between)
+	}
+
+	// GetEdge returns the parallel edge with the lowest ID
+	edge, err := g.GetEdge("A", "B")
+	if err != nil {
+		t.Fatalf("Failed to get edge: %v", err)
+	}
+	if edge.ID != "e1" {
+		t.Errorf("Expected edge 'e1', got '%s'", edge.ID)
+	}
+
+	neighbors, _ := g.Neighbors("A")
+	if len(neighbors) != 2 {
+		t.Errorf("Expected 2 neighbors, got %d", len(neighbors))
+	}
+	incoming, _ := g.Incoming("B")
+	if len(incoming) != 2 {
+		t.Errorf("Expected 2 incoming edges, got %d", len(incoming))
+	}
+
+	// OutEdges keeps its keying by target node; OutEdgesByID has every edge
+	if edge := g.OutEdges()["A"]["B"]; edge == nil || edge.ID != "e1" {
+		t.Errorf("Expected OutEdges to map A to B through e1, got %v", edge)
+	}
+	byID := g.OutEdgesByID()["A"]
+	if len(byID) != 2 || byID["e1"] == nil || byID["e2"] == nil {
+		t.Errorf("Expected OutEdgesByID to hold e1 and e2, got %v", byID)
+	}
+}
+
+// TestGetAndDeleteEdgeByID tests addressing a single parallel edge by ID
+func TestGetAndDeleteEdgeByID(t *testing.T) {
+	g := NewGraph()
+
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e2", From: "A", To: "B", Weight: 2.0})
+
+	edge, err := g.GetEdgeByID("e2")
+	if err != nil {
+		t.Fatalf("Failed to get edge by ID: %v", err)
+	}
+	if edge.Weight != 2.0 {
+		t.Errorf("Expected weight 2.0, got %f", edge.Weight)
+	}
+
+	if err := g.DeleteEdgeByID("e1"); err != nil {
+		t.Fatalf("Failed to delete edge by ID: %v", err)
+	}
+	if _, err := g.GetEdgeByID("e1"); err == nil {
+		t.Error("Expected error when getting deleted edge, got nil")
+	}
+
+	// The parallel edge survives
+	edge, err = g.GetEdge("A", "B")
+	if err != nil {
+		t.Fatalf("Failed to get remaining edge: %v", err)
+	}
+	if edge.ID != "e2" {
+		t.Errorf("Expected edge 'e2', got '%s'", edge.ID)
+	}
+
+	if err := g.DeleteEdgeByID("e1"); err == nil {
+		t.Error("Expected error when deleting missing edge, got nil")
+	}
+}
+
+// TestDeleteEdgeRemovesParallelEdges tests that DeleteEdge removes every edge
+// between a pair of nodes
+func TestDeleteEdgeRemovesParallelEdges(t *testing.T) {
+	g := NewGraph()
+
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e2", From: "A", To: "B", Weight: 2.0})
+	g.AddEdge(&Edge{ID: "e3", From: "B", To: "A", Weight: 3.0})
+
+	if err := g.DeleteEdge("A", "B"); err != nil {
+		t.Fatalf("Failed to delete edges: %v", err)
+	}
+	for _, id := range []string{"e1", "e2"} {
+		if _, err := g.GetEdgeByID(id); err == nil {
+			t.Errorf("Expected edge '%s' to be deleted", id)
+		}
+	}
+	if _, err := g.GetEdgeByID("e3"); err != nil {
+		t.Errorf("Expected reverse edge to survive: %v", err)
+	}
+}
+
 // TestSelfLoop tests self-loop edges
 func TestSelfLoop(t *testing.T) {
 	g := NewGraph()
@@ -385,6 +512,32 @@ func TestDijkstraNoPath(t *testing.T) {
 	}
 }
 
+// TestDijkstraParallelEdges tests that Dijkstra's algorithm uses the cheapest
+// of several parallel edges
+func TestDijkstraParallelEdges(t *testing.T) {
+	g := NewGraph()
+
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddNode(&Node{ID: "C"})
+
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 5.0})
+	g.AddEdge(&Edge{ID: "e2", From: "A", To: "B", Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e3", From: "B", To: "C", Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e4", From: "A", To: "C", Weight: 3.0})
+
+	path, distance, err := g.ShortestPath("A", "C")
+	if err != nil {
+		t.Fatalf("ShortestPath failed: %v", err)
+	}
+	if len(path) != 3 || path[1] != "B" {
+		t.Errorf("Expected path [A B C], got %v", path)
+	}
+	if distance != 2.0 {
+		t.Errorf("Expected distance 2.0, got %f", distance)
+	}
+}
+
 // TestDijkstraWithSelfLoop tests Dijkstra's algorithm with self-loops
 func TestDijkstraWithSelfLoop(t *testing.T) {
 	g := NewGraph()
@@ -411,3 +564,98 @@ func TestDijkstraWithSelfLoop(t *testing.T) {
 		t.Errorf("Expected distance 2.0, got %f", distance)
 	}
 }
+
+// TestNodesByLabel tests looking up nodes through the label index
+func TestNodesByLabel(t *testing.T) {
+	g := NewGraph()
+
+	g.AddNode(&Node{ID: "bob", Labels: []string{"Person"}})
+	g.AddNode(&Node{ID: "alice", Labels: []string{"Person", "Admin"}})
+	g.AddNode(&Node{ID: "acme", Labels: []string{"Company"}})
+
+	people := g.NodesByLabel("Person")
+	if len(people) != 2 || people[0].ID != "alice" || people[1].ID != "bob" {
+		t.Errorf("Expected people [alice bob], got %v", people)
+	}
+	if !people[0].HasLabel("Admin") {
+		t.Error("Expected alice to carry the Admin label")
+	}
+
+	g.DeleteNode("alice")
+	if admins := g.NodesByLabel("Admin"); len(admins) != 0 {
+		t.Errorf("Expected no admins after delete, got %v", admins)
+	}
+	if people := g.NodesByLabel("Person"); len(people) != 1 {
+		t.Errorf("Expected 1 person after delete, got %d", len(people))
+	}
+}
+
+// TestEdgesByTypeAndFilteredNeighbors tests the edge type index and
+// type-filtered adjacency
+func TestEdgesByTypeAndFilteredNeighbors(t *testing.T) {
+	g := NewGraph()
+
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddNode(&Node{ID: "C"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Type: "KNOWS"})
+	g.AddEdge(&Edge{ID: "e2", From: "A", To: "B", Type: "PAID"})
+	g.AddEdge(&Edge{ID: "e3", From: "A", To: "C", Type: "KNOWS"})
+	g.AddEdge(&Edge{ID: "e4", From: "C", To: "B"})
+
+	knows := g.EdgesByType("KNOWS")
+	if len(knows) != 2 || knows[0].ID != "e1" || knows[1].ID != "e3" {
+		t.Errorf("Expected KNOWS edges [e1 e3], got %v", knows)
+	}
+	if untyped := g.EdgesByType(""); len(untyped) != 1 {
+		t.Errorf("Expected 1 untyped edge, got %d", len(untyped))
+	}
+
+	neighbors, err := g.Neighbors("A", "PAID")
+	if err != nil {
+		t.Fatalf("Failed to get neighbors: %v", err)
+	}
+	if len(neighbors) != 1 || neighbors[0].ID != "e2" {
+		t.Errorf("Expected PAID neighbors [e2], got %v", neighbors)
+	}
+
+	incoming, err := g.Incoming("B", "KNOWS", "PAID")
+	if err != nil {
+		t.Fatalf("Failed to get incoming edges: %v", err)
+	}
+	if len(incoming) != 2 {
+		t.Errorf("Expected 2 typed incoming edges, got %d", len(incoming))
+	}
+
+	g.DeleteEdgeByID("e1")
+	if knows := g.EdgesByType("KNOWS"); len(knows) != 1 {
+		t.Errorf("Expected 1 KNOWS edge after delete, got %d", len(knows))
+	}
+}
+
+// buildGraph builds a graph from nodes and edges, failing the test if any of
+// them cannot be added
+func buildGraph(t testing.TB, nodes []*Node, edges []*Edge, opts ...Option) Graph {
+	t.Helper()
+	g := NewGraph(opts...)
+	for _, node := range nodes {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("Failed to add node: %v", err)
+		}
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("Failed to add edge: %v", err)
+		}
+	}
+	return g
+}
+
+// nodesWithIDs returns bare nodes with the given IDs
+func nodesWithIDs(ids ...string) []*Node {
+	nodes := make([]*Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = &Node{ID: id}
+	}
+	return nodes
+}
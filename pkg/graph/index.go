@@ -0,0 +1,545 @@
+package graph
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// IndexKind selects the data structure backing a property index.
+type IndexKind int
+
+const (
+	// HashIndex supports equality lookups only.
+	HashIndex IndexKind = iota
+	// OrderedIndex keeps values sorted and supports equality, range and
+	// prefix lookups.
+	OrderedIndex
+)
+
+func (k IndexKind) String() string {
+	switch k {
+	case HashIndex:
+		return "hash"
+	case OrderedIndex:
+		return "ordered"
+	default:
+		return fmt.Sprintf("IndexKind(%d)", int(k))
+	}
+}
+
+func (k IndexKind) MarshalText() ([]byte, error) {
+	if k != HashIndex && k != OrderedIndex {
+		return nil, fmt.Errorf("unknown index kind %d", int(k))
+	}
+	return []byte(k.String()), nil
+}
+
+func (k *IndexKind) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "hash":
+		*k = HashIndex
+	case "ordered":
+		*k = OrderedIndex
+	default:
+		return fmt.Errorf("unknown index kind %q", text)
+	}
+	return nil
+}
+
+// IndexDefinition describes a property index. An empty Label indexes the
+// property on every node.
+type IndexDefinition struct {
+	Label    string
+	Property string
+	Kind     IndexKind
+}
+
+type indexKey struct {
+	label, property string
+}
+
+// valueKind orders indexable values of different types relative to each
+// other: all booleans sort before all numbers, which sort before all strings.
+type valueKind int
+
+const (
+	boolValue valueKind = iota
+	numberValue
+	stringValue
+)
+
+// indexValue is the normalized, comparable form of a property value. All
+// numeric types are widened to float64 so that values survive a JSON round
+// trip through BoltPersist unchanged. An integer too large for float64 to
+// hold exactly keeps the rounding in off, so that it stays distinct from its
+// neighbours and from every float.
+type indexValue struct {
+	kind valueKind
+	num  float64
+	off  int64
+	str  string
+}
+
+// normalizeValue returns the indexValue for a property value, or false if the
+// value cannot be indexed. NaN is not equal to itself, so it could never be
+// looked up again and is rejected.
+func normalizeValue(v any) (indexValue, bool) {
+	switch x := v.(type) {
+	case bool:
+		if x {
+			return indexValue{kind: boolValue, num: 1}, true
+		}
+		return indexValue{kind: boolValue}, true
+	case string:
+		return indexValue{kind: stringValue, str: x}, true
+	case int:
+		return intValue(int64(x)), true
+	case int8:
+		return indexValue{kind: numberValue, num: float64(x)}, true
+	case int16:
+		return indexValue{kind: numberValue, num: float64(x)}, true
+	case int32:
+		return indexValue{kind: numberValue, num: float64(x)}, true
+	case int64:
+		return intValue(x), true
+	case uint:
+		return uintValue(uint64(x)), true
+	case uint8:
+		return indexValue{kind: numberValue, num: float64(x)}, true
+	case uint16:
+		return indexValue{kind: numberValue, num: float64(x)}, true
+	case uint32:
+		return indexValue{kind: numberValue, num: float64(x)}, true
+	case uint64:
+		return uintValue(x), true
+	case float32:
+		if math.IsNaN(float64(x)) {
+			return indexValue{}, false
+		}
+		return indexValue{kind: numberValue, num: float64(x)}, true
+	case float64:
+		if math.IsNaN(x) {
+			return indexValue{}, false
+		}
+		return indexValue{kind: numberValue, num: x}, true
+	default:
+		return indexValue{}, false
+	}
+}
+
+// intValue returns the indexValue of an integer, which float64 may round.
+func intValue(x int64) indexValue {
+	f := float64(x)
+	if f == 1<<63 {
+		// Rounded up out of the range of int64
+		return indexValue{kind: numberValue, num: f, off: x - math.MaxInt64 - 1}
+	}
+	return indexValue{kind: numberValue, num: f, off: x - int64(f)}
+}
+
+// uintValue returns the indexValue of an unsigned integer, which float64 may
+// round.
+func uintValue(x uint64) indexValue {
+	f := float64(x)
+	if f == 1<<64 {
+		// Rounded up out of the range of uint64; x - 1<<64 wraps round to x
+		return indexValue{kind: numberValue, num: f, off: int64(x)}
+	}
+	return indexValue{kind: numberValue, num: f, off: int64(x - uint64(f))}
+}
+
+func compareValues(a, b indexValue) int {
+	if c := cmp.Compare(a.kind, b.kind); c != 0 {
+		return c
+	}
+	if a.kind == stringValue {
+		return strings.Compare(a.str, b.str)
+	}
+	return cmp.Or(cmp.Compare(a.num, b.num), cmp.Compare(a.off, b.off))
+}
+
+// valueRange is an inclusive range of values of a single kind. A nil bound is
+// unbounded on that side.
+type valueRange struct {
+	kind   valueKind
+	lo, hi *indexValue
+}
+
+func newValueRange(lo, hi any) (valueRange, error) {
+	var r valueRange
+	kindSet := false
+	for _, bound := range []struct {
+		v   any
+		dst **indexValue
+	}{{lo, &r.lo}, {hi, &r.hi}} {
+		if bound.v == nil {
+			continue
+		}
+		iv, ok := normalizeValue(bound.v)
+		if !ok {
+			return r, fmt.Errorf("value %v of type %T cannot be indexed", bound.v, bound.v)
+		}
+		if kindSet && iv.kind != r.kind {
+			return r, fmt.Errorf("range bounds %v and %v are of different types", lo, hi)
+		}
+		r.kind, kindSet = iv.kind, true
+		*bound.dst = &iv
+	}
+	if !kindSet {
+		return r, fmt.Errorf("range needs at least one bound")
+	}
+	return r, nil
+}
+
+func (r valueRange) contains(v indexValue) bool {
+	if v.kind != r.kind {
+		return false
+	}
+	if r.lo != nil && compareValues(v, *r.lo) < 0 {
+		return false
+	}
+	if r.hi != nil && compareValues(v, *r.hi) > 0 {
+		return false
+	}
+	return true
+}
+
+type indexEntry struct {
+	value  indexValue
+	nodeID string
+}
+
+func compareEntries(a, b indexEntry) int {
+	if c := compareValues(a.value, b.value); c != 0 {
+		return c
+	}
+	return strings.Compare(a.nodeID, b.nodeID)
+}
+
+// propertyIndex maps the values of one property to the IDs of the nodes
+// holding them. Hash indexes use a map of sets; ordered indexes keep a slice
+// of entries sorted by value and then node ID. Like the store's containers,
+// an index is copied before being written in a later epoch than the one it
+// was created or last copied in.
+type propertyIndex struct {
+	def     IndexDefinition
+	hash    map[indexValue]map[string]struct{}
+	ordered []indexEntry
+	epoch   uint64
+}
+
+func newPropertyIndex(def IndexDefinition, epoch uint64) *propertyIndex {
+	idx := &propertyIndex{def: def, epoch: epoch}
+	if def.Kind == HashIndex {
+		idx.hash = make(map[indexValue]map[string]struct{})
+	}
+	return idx
+}
+
+func (idx *propertyIndex) clone(epoch uint64) *propertyIndex {
+	c := &propertyIndex{def: idx.def, ordered: slices.Clone(idx.ordered), epoch: epoch}
+	if idx.hash != nil {
+		c.hash = make(map[indexValue]map[string]struct{}, len(idx.hash))
+		for v, ids := range idx.hash {
+			c.hash[v] = maps.Clone(ids)
+		}
+	}
+	return c
+}
+
+// covers reports whether the node falls under the index definition, returning
+// its normalized value when it does.
+func (idx *propertyIndex) covers(node *Node) (indexValue, bool) {
+	if idx.def.Label != "" && !node.HasLabel(idx.def.Label) {
+		return indexValue{}, false
+	}
+	v, exists := node.Properties[idx.def.Property]
+	if !exists {
+		return indexValue{}, false
+	}
+	return normalizeValue(v)
+}
+
+func (idx *propertyIndex) add(node *Node) {
+	v, ok := idx.covers(node)
+	if !ok {
+		return
+	}
+	if idx.def.Kind == HashIndex {
+		if idx.hash[v] == nil {
+			idx.hash[v] = make(map[string]struct{})
+		}
+		idx.hash[v][node.ID] = struct{}{}
+		return
+	}
+	entry := indexEntry{value: v, nodeID: node.ID}
+	i, found := slices.BinarySearchFunc(idx.ordered, entry, compareEntries)
+	if !found {
+		idx.ordered = slices.Insert(idx.ordered, i, entry)
+	}
+}
+
+// build fills a new index with the given nodes. An ordered index sorts its
+// entries once rather than inserting them one at a time.
+func (idx *propertyIndex) build(nodes map[string]*Node) {
+	if idx.def.Kind == HashIndex {
+		for _, node := range nodes {
+			idx.add(node)
+		}
+		return
+	}
+	for _, node := range nodes {
+		if v, ok := idx.covers(node); ok {
+			idx.ordered = append(idx.ordered, indexEntry{value: v, nodeID: node.ID})
+		}
+	}
+	slices.SortFunc(idx.ordered, compareEntries)
+}
+
+func (idx *propertyIndex) remove(node *Node) {
+	v, ok := idx.covers(node)
+	if !ok {
+		return
+	}
+	if idx.def.Kind == HashIndex {
+		delete(idx.hash[v], node.ID)
+		if len(idx.hash[v]) == 0 {
+			delete(idx.hash, v)
+		}
+		return
+	}
+	entry := indexEntry{value: v, nodeID: node.ID}
+	if i, found := slices.BinarySearchFunc(idx.ordered, entry, compareEntries); found {
+		idx.ordered = slices.Delete(idx.ordered, i, i+1)
+	}
+}
+
+func (idx *propertyIndex) lookup(v indexValue) []string {
+	var ids []string
+	if idx.def.Kind == HashIndex {
+		for id := range idx.hash[v] {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	i, _ := slices.BinarySearchFunc(idx.ordered, indexEntry{value: v}, compareEntries)
+	for ; i < len(idx.ordered) && idx.ordered[i].value == v; i++ {
+		ids = append(ids, idx.ordered[i].nodeID)
+	}
+	return ids
+}
+
+// scan returns the IDs of ordered entries from the first value not below
+// start, for as long as match holds.
+func (idx *propertyIndex) scan(start indexValue, match func(indexValue) bool) []string {
+	var ids []string
+	i, _ := slices.BinarySearchFunc(idx.ordered, indexEntry{value: start}, compareEntries)
+	for ; i < len(idx.ordered) && match(idx.ordered[i].value); i++ {
+		ids = append(ids, idx.ordered[i].nodeID)
+	}
+	return ids
+}
+
+func (idx *propertyIndex) lookupRange(r valueRange) []string {
+	// Without a lower bound, start at the smallest value of the range's kind.
+	start := indexValue{kind: r.kind}
+	if r.kind == numberValue {
+		start.num = math.Inf(-1)
+	}
+	if r.lo != nil {
+		start = *r.lo
+	}
+	return idx.scan(start, func(v indexValue) bool {
+		return v.kind == r.kind && (r.hi == nil || compareValues(v, *r.hi) <= 0)
+	})
+}
+
+func (idx *propertyIndex) lookupPrefix(prefix string) []string {
+	return idx.scan(indexValue{kind: stringValue, str: prefix}, func(v indexValue) bool {
+		return v.kind == stringValue && strings.HasPrefix(v.str, prefix)
+	})
+}
+
+// createIndex builds an index over a node property, restricted to nodes with
+// the given label unless the label is empty. The index is kept up to date as
+// nodes are added, deleted and updated.
+func (s *store) createIndex(label, property string, kind IndexKind) error {
+	if property == "" {
+		return fmt.Errorf("index property must not be empty")
+	}
+	if kind != HashIndex && kind != OrderedIndex {
+		return fmt.Errorf("unknown index kind %d", int(kind))
+	}
+	key := indexKey{label, property}
+	if _, exists := s.indexes.m[key]; exists {
+		return fmt.Errorf("index on %q.%q already exists", label, property)
+	}
+	idx := newPropertyIndex(IndexDefinition{Label: label, Property: property, Kind: kind}, s.epoch)
+	idx.build(s.nodes.m)
+	s.indexes.mut(s.epoch)[key] = idx
+	return nil
+}
+
+func (s *store) dropIndex(label, property string) error {
+	key := indexKey{label, property}
+	if _, exists := s.indexes.m[key]; !exists {
+		return fmt.Errorf("index on %q.%q not found", label, property)
+	}
+	delete(s.indexes.mut(s.epoch), key)
+	return nil
+}
+
+// Indexes returns the definitions of every index, sorted by label and
+// property.
+func (s *store) Indexes() []IndexDefinition {
+	defs := make([]IndexDefinition, 0, len(s.indexes.m))
+	for _, idx := range s.indexes.m {
+		defs = append(defs, idx.def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		if defs[i].Label != defs[j].Label {
+			return defs[i].Label < defs[j].Label
+		}
+		return defs[i].Property < defs[j].Property
+	})
+	return defs
+}
+
+// FindNodes returns the nodes with the given label whose property equals
+// value, sorted by ID. An empty label matches every node. The lookup uses an
+// index on the label and property when one exists and scans otherwise.
+func (s *store) FindNodes(label, property string, value any) ([]*Node, error) {
+	v, ok := normalizeValue(value)
+	if !ok {
+		return nil, fmt.Errorf("value %v of type %T cannot be indexed", value, value)
+	}
+	if idx, exists := s.indexes.m[indexKey{label, property}]; exists {
+		return s.nodesByID(idx.lookup(v)), nil
+	}
+	return s.scanNodes(label, property, func(nv indexValue) bool { return nv == v }), nil
+}
+
+// FindNodesInRange returns the nodes with the given label whose property lies
+// within the inclusive range [lo, hi], sorted by ID. Either bound may be nil
+// to leave that side open, and only values of the same type as the bounds
+// match. An ordered index is used when one exists.
+func (s *store) FindNodesInRange(label, property string, lo, hi any) ([]*Node, error) {
+	r, err := newValueRange(lo, hi)
+	if err != nil {
+		return nil, err
+	}
+	if idx, exists := s.indexes.m[indexKey{label, property}]; exists && idx.def.Kind == OrderedIndex {
+		return s.nodesByID(idx.lookupRange(r)), nil
+	}
+	return s.scanNodes(label, property, r.contains), nil
+}
+
+// FindNodesWithPrefix returns the nodes with the given label whose string
+// property starts with prefix, sorted by ID. An ordered index is used when
+// one exists.
+func (s *store) FindNodesWithPrefix(label, property, prefix string) ([]*Node, error) {
+	if idx, exists := s.indexes.m[indexKey{label, property}]; exists && idx.def.Kind == OrderedIndex {
+		return s.nodesByID(idx.lookupPrefix(prefix)), nil
+	}
+	return s.scanNodes(label, property, func(v indexValue) bool {
+		return v.kind == stringValue && strings.HasPrefix(v.str, prefix)
+	}), nil
+}
+
+// indexNode adds a node to the label and property indexes and to the value
+// owners of unique constraints.
+func (s *store) indexNode(node *Node) {
+	for _, label := range node.Labels {
+		setInner(&s.labels, s.epoch, label, node.ID, node)
+	}
+	for key, idx := range s.indexes.m {
+		if _, ok := idx.covers(node); ok {
+			s.mutIndex(key).add(node)
+		}
+	}
+	for key, c := range s.constraints.m {
+		if c.applies(NodeTarget, node.HasLabel(c.def.Label)) {
+			s.mutConstraint(key).add(node.ID, node.Properties)
+		}
+	}
+}
+
+// unindexNode reverses indexNode.
+func (s *store) unindexNode(node *Node) {
+	for _, label := range node.Labels {
+		deleteInner(&s.labels, s.epoch, label, node.ID)
+	}
+	for key, idx := range s.indexes.m {
+		if _, ok := idx.covers(node); ok {
+			s.mutIndex(key).remove(node)
+		}
+	}
+	for key, c := range s.constraints.m {
+		if c.applies(NodeTarget, node.HasLabel(c.def.Label)) {
+			s.mutConstraint(key).remove(node.ID, node.Properties)
+		}
+	}
+}
+
+// indexEdge adds an edge to the type index and to the value owners of unique
+// constraints.
+func (s *store) indexEdge(edge *Edge) {
+	setInner(&s.types, s.epoch, edge.Type, edge.ID, edge)
+	for key, c := range s.constraints.m {
+		if c.applies(EdgeTarget, edge.Type == c.def.Label) {
+			s.mutConstraint(key).add(edge.ID, edge.Properties)
+		}
+	}
+}
+
+// unindexEdge reverses indexEdge.
+func (s *store) unindexEdge(edge *Edge) {
+	deleteInner(&s.types, s.epoch, edge.Type, edge.ID)
+	for key, c := range s.constraints.m {
+		if c.applies(EdgeTarget, edge.Type == c.def.Label) {
+			s.mutConstraint(key).remove(edge.ID, edge.Properties)
+		}
+	}
+}
+
+// mutIndex returns a version of an existing index that may be written in the
+// store's current epoch.
+func (s *store) mutIndex(key indexKey) *propertyIndex {
+	idx := s.indexes.m[key]
+	if idx.epoch != s.epoch {
+		idx = idx.clone(s.epoch)
+		s.indexes.mut(s.epoch)[key] = idx
+	}
+	return idx
+}
+
+// nodesByID resolves node IDs, returning copies of the nodes sorted by ID.
+func (s *store) nodesByID(ids []string) []*Node {
+	nodes := make([]*Node, 0, len(ids))
+	for _, id := range ids {
+		nodes = append(nodes, s.nodes.m[id].clone())
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// scanNodes returns copies of the nodes with the given label whose property
+// matches, sorted by ID.
+func (s *store) scanNodes(label, property string, match func(indexValue) bool) []*Node {
+	var nodes []*Node
+	for _, node := range s.nodes.m {
+		if label != "" && !node.HasLabel(label) {
+			continue
+		}
+		if v, ok := normalizeValue(node.Properties[property]); ok && match(v) {
+			nodes = append(nodes, node.clone())
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
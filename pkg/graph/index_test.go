@@ -0,0 +1,270 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+// newPeopleGraph builds a small graph of labelled nodes with indexable
+// properties
+func newPeopleGraph(t *testing.T) Graph {
+	t.Helper()
+	return buildGraph(t, []*Node{
+		{ID: "alice", Labels: []string{"Person"}, Properties: map[string]any{"age": 34, "email": "alice@example.com"}},
+		{ID: "bob", Labels: []string{"Person"}, Properties: map[string]any{"age": 27, "email": "bob@example.com"}},
+		{ID: "carol", Labels: []string{"Person"}, Properties: map[string]any{"age": 34.0, "email": "carol@example.org"}},
+		{ID: "acme", Labels: []string{"Company"}, Properties: map[string]any{"age": 34, "email": "info@acme.com"}},
+	}, nil)
+}
+
+func nodeIDs(nodes []*Node) []string {
+	ids := make([]string, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.ID
+	}
+	return ids
+}
+
+func equalIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestFindNodesWithAndWithoutIndex tests that equality lookups return the same
+// result with a hash index, an ordered index and a full scan
+func TestFindNodesWithAndWithoutIndex(t *testing.T) {
+	for _, kind := range []IndexKind{-1, HashIndex, OrderedIndex} {
+		g := newPeopleGraph(t)
+		if kind >= 0 {
+			if err := g.CreateIndex("Person", "age", kind); err != nil {
+				t.Fatalf("Failed to create %v index: %v", kind, err)
+			}
+		}
+
+		nodes, err := g.FindNodes("Person", "age", 34)
+		if err != nil {
+			t.Fatalf("FindNodes failed: %v", err)
+		}
+		if got, want := nodeIDs(nodes), []string{"alice", "carol"}; !equalIDs(got, want) {
+			t.Errorf("Kind %v: expected %v, got %v", kind, want, got)
+		}
+
+		// The empty label matches nodes of every label
+		nodes, _ = g.FindNodes("", "age", 34)
+		if got, want := nodeIDs(nodes), []string{"acme", "alice", "carol"}; !equalIDs(got, want) {
+			t.Errorf("Kind %v: expected %v, got %v", kind, want, got)
+		}
+	}
+}
+
+// TestFindNodesInRange tests inclusive and open-ended range lookups
+func TestFindNodesInRange(t *testing.T) {
+	for _, kind := range []IndexKind{-1, HashIndex, OrderedIndex} {
+		g := newPeopleGraph(t)
+		if kind >= 0 {
+			g.CreateIndex("Person", "age", kind)
+		}
+
+		nodes, err := g.FindNodesInRange("Person", "age", 27, 33)
+		if err != nil {
+			t.Fatalf("FindNodesInRange failed: %v", err)
+		}
+		if got, want := nodeIDs(nodes), []string{"bob"}; !equalIDs(got, want) {
+			t.Errorf("Kind %v: expected %v, got %v", kind, want, got)
+		}
+
+		nodes, _ = g.FindNodesInRange("Person", "age", nil, 40)
+		if got, want := nodeIDs(nodes), []string{"alice", "bob", "carol"}; !equalIDs(got, want) {
+			t.Errorf("Kind %v: expected %v, got %v", kind, want, got)
+		}
+
+		nodes, _ = g.FindNodesInRange("Person", "age", 30, nil)
+		if got, want := nodeIDs(nodes), []string{"alice", "carol"}; !equalIDs(got, want) {
+			t.Errorf("Kind %v: expected %v, got %v", kind, want, got)
+		}
+	}
+
+	g := newPeopleGraph(t)
+	if _, err := g.FindNodesInRange("Person", "age", 1, "z"); err == nil {
+		t.Error("Expected error for mixed-type range bounds, got nil")
+	}
+	if _, err := g.FindNodesInRange("Person", "age", nil, nil); err == nil {
+		t.Error("Expected error for unbounded range, got nil")
+	}
+}
+
+// TestFindNodesWithPrefix tests prefix lookups over an ordered index
+func TestFindNodesWithPrefix(t *testing.T) {
+	g := newPeopleGraph(t)
+	g.CreateIndex("", "email", OrderedIndex)
+
+	nodes, err := g.FindNodesWithPrefix("", "email", "b")
+	if err != nil {
+		t.Fatalf("FindNodesWithPrefix failed: %v", err)
+	}
+	if got, want := nodeIDs(nodes), []string{"bob"}; !equalIDs(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestIndexMaintenance tests that indexes follow node additions and deletions
+func TestIndexMaintenance(t *testing.T) {
+	g := newPeopleGraph(t)
+	g.CreateIndex("Person", "email", HashIndex)
+
+	g.AddNode(&Node{ID: "dave", Labels: []string{"Person"}, Properties: map[string]any{"email": "dave@example.com"}})
+	nodes, _ := g.FindNodes("Person", "email", "dave@example.com")
+	if got, want := nodeIDs(nodes), []string{"dave"}; !equalIDs(got, want) {
+		t.Errorf("Expected %v after add, got %v", want, got)
+	}
+
+	g.DeleteNode("dave")
+	nodes, _ = g.FindNodes("Person", "email", "dave@example.com")
+	if len(nodes) != 0 {
+		t.Errorf("Expected no nodes after delete, got %v", nodeIDs(nodes))
+	}
+}
+
+// TestIndexNaN tests that NaN values are left out of indexes
+func TestIndexNaN(t *testing.T) {
+	g := NewGraph()
+	g.CreateIndex("", "x", HashIndex)
+	g.CreateIndex("", "y", OrderedIndex)
+
+	nan := map[string]any{"x": math.NaN(), "y": float32(math.NaN())}
+	if err := g.AddNode(&Node{ID: "A", Properties: nan}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	if _, err := g.FindNodes("", "x", math.NaN()); err == nil {
+		t.Error("Expected error when looking up NaN, got nil")
+	}
+	if err := g.DeleteNode("A"); err != nil {
+		t.Fatalf("Failed to delete node: %v", err)
+	}
+}
+
+// TestIndexLargeIntegers tests that integers too large for float64 to hold
+// exactly stay distinct
+func TestIndexLargeIntegers(t *testing.T) {
+	g := NewGraph()
+	g.CreateIndex("", "n", HashIndex)
+	g.CreateIndex("", "m", OrderedIndex)
+
+	values := map[string]any{
+		"a": int64(1 << 53),
+		"b": int64(1<<53 + 1),
+		"c": uint64(math.MaxUint64),
+		"d": uint64(math.MaxUint64 - 1),
+		"e": int64(math.MaxInt64),
+	}
+	for id, v := range values {
+		if err := g.AddNode(&Node{ID: id, Properties: map[string]any{"n": v, "m": v}}); err != nil {
+			t.Fatalf("Failed to add node %s: %v", id, err)
+		}
+	}
+	for id, v := range values {
+		nodes, _ := g.FindNodes("", "n", v)
+		if got := nodeIDs(nodes); !equalIDs(got, []string{id}) {
+			t.Errorf("Expected [%s] for %v, got %v", id, v, got)
+		}
+	}
+
+	// A float equal to an integer finds it
+	nodes, _ := g.FindNodes("", "n", float64(1<<53))
+	if got := nodeIDs(nodes); !equalIDs(got, []string{"a"}) {
+		t.Errorf("Expected [a] for 2^53 as a float, got %v", got)
+	}
+	nodes, _ = g.FindNodesInRange("", "m", int64(1<<53+1), uint64(math.MaxUint64-1))
+	if got := nodeIDs(nodes); !equalIDs(got, []string{"b", "d", "e"}) {
+		t.Errorf("Expected [b d e] in range, got %v", got)
+	}
+}
+
+// TestCreateAndDropIndex tests index lifecycle errors
+func TestCreateAndDropIndex(t *testing.T) {
+	g := NewGraph()
+
+	if err := g.CreateIndex("Person", "email", HashIndex); err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	if err := g.CreateIndex("Person", "email", OrderedIndex); err == nil {
+		t.Error("Expected error when creating duplicate index, got nil")
+	}
+	if err := g.CreateIndex("Person", "", HashIndex); err == nil {
+		t.Error("Expected error when creating index without property, got nil")
+	}
+	if defs := g.Indexes(); len(defs) != 1 || defs[0].Kind != HashIndex {
+		t.Errorf("Expected one hash index, got %v", defs)
+	}
+	if err := g.DropIndex("Person", "email"); err != nil {
+		t.Fatalf("Failed to drop index: %v", err)
+	}
+	if err := g.DropIndex("Person", "email"); err == nil {
+		t.Error("Expected error when dropping missing index, got nil")
+	}
+}
+
+// TestCreateOrderedIndexOnExistingNodes tests that an index built over existing nodes is sorted
+func TestCreateOrderedIndexOnExistingNodes(t *testing.T) {
+	g := NewGraph()
+	for i := 0; i < 100; i++ {
+		node := &Node{ID: fmt.Sprintf("n%02d", i), Labels: []string{"Item"}, Properties: map[string]any{"rank": (i * 37) % 100}}
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("Failed to add node: %v", err)
+		}
+	}
+	if err := g.CreateIndex("Item", "rank", OrderedIndex); err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+
+	nodes, err := g.FindNodesInRange("Item", "rank", 10, 14)
+	if err != nil {
+		t.Fatalf("Failed to find nodes: %v", err)
+	}
+	if len(nodes) != 5 {
+		t.Fatalf("Expected 5 nodes, got %d", len(nodes))
+	}
+	for _, node := range nodes {
+		if rank := node.Properties["rank"].(int); rank < 10 || rank > 14 {
+			t.Errorf("Expected rank within [10, 14], got %d", rank)
+		}
+	}
+	idx := g.(*graphImpl).s.indexes.m[indexKey{"Item", "rank"}]
+	if len(idx.ordered) != 100 || !slices.IsSortedFunc(idx.ordered, compareEntries) {
+		t.Errorf("Expected 100 sorted index entries, got %d", len(idx.ordered))
+	}
+}
+
+func BenchmarkCreateIndex(b *testing.B) {
+	for _, kind := range []IndexKind{HashIndex, OrderedIndex} {
+		b.Run(kind.String(), func(b *testing.B) {
+			g := NewGraph()
+			for i := 0; i < 50000; i++ {
+				node := &Node{ID: fmt.Sprintf("n%d", i), Labels: []string{"Item"}, Properties: map[string]any{"rank": (i * 7919) % 50000}}
+				if err := g.AddNode(node); err != nil {
+					b.Fatalf("Failed to add node: %v", err)
+				}
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := g.CreateIndex("Item", "rank", kind); err != nil {
+					b.Fatalf("Failed to create index: %v", err)
+				}
+				b.StopTimer()
+				if err := g.DropIndex("Item", "rank"); err != nil {
+					b.Fatalf("Failed to drop index: %v", err)
+				}
+				b.StartTimer()
+			}
+		})
+	}
+}
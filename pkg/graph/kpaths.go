@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// KShortestPaths finds up to k loopless paths from source to target in order
+// of increasing weight using Yen's algorithm. Paths of equal weight are
+// ordered by their number of edges and then by the IDs of their edges, so the
+// result is the same on every run. Parallel edges give rise to distinct
+// paths. Fewer than k paths are returned when no more exist. Like
+// ShortestPath, it fails with ErrNegativeWeight if any edge has a negative
+// weight.
+func (s *store) KShortestPaths(source, target string, k int) ([]*Path, error) {
+	return s.KShortestPathsContext(context.Background(), source, target, k)
+}
+
+// KShortestPathsContext is KShortestPaths that gives up with the context's
+// error once the context is done, or with ErrVisitLimit once its searches
+// settle more nodes in total than allowed.
+func (s *store) KShortestPathsContext(ctx context.Context, source, target string, k int) ([]*Path, error) {
+	if _, exists := s.nodes.m[source]; !exists {
+		return nil, fmt.Errorf("source node %q not found", source)
+	}
+	if _, exists := s.nodes.m[target]; !exists {
+		return nil, fmt.Errorf("target node %q not found", target)
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("k must be positive, got %d", k)
+	}
+	if s.negative > 0 {
+		return nil, ErrNegativeWeight
+	}
+
+	search := s.newSearch(ctx)
+	first, err := s.dijkstra(search, source, target, edgeWeight)
+	if err != nil {
+		return nil, err
+	}
+	best, err := first.PathTo(target)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := []*Path{best}
+	seen := map[string]bool{pathKey(best): true}
+	var candidates []*Path
+
+	for len(accepted) < k {
+		last := accepted[len(accepted)-1]
+
+		// Deviate from the last accepted path at each of its nodes in turn
+		for i := 0; i < len(last.Edges); i++ {
+			spur := last.Nodes[i]
+			rootEdges := last.Edges[:i]
+
+			// Forbid the next edge of every accepted path sharing this
+			// root, so that the spur path differs from all of them, and the
+			// root's nodes, so that the whole path has no loops
+			removedEdges := make(map[string]bool)
+			for _, p := range accepted {
+				if len(p.Edges) > i && sameEdges(p.Edges[:i], rootEdges) {
+					removedEdges[p.Edges[i].ID] = true
+				}
+			}
+			removedNodes := make(map[string]bool, i)
+			for _, id := range last.Nodes[:i] {
+				removedNodes[id] = true
+			}
+
+			tree, err := s.dijkstra(search, spur, target, func(edge *Edge, from string) (float64, bool) {
+				if removedEdges[edge.ID] || removedNodes[edge.Other(from)] {
+					return 0, false
+				}
+				return edge.Weight, true
+			})
+			if err != nil {
+				return nil, err
+			}
+			spurPath, err := tree.PathTo(target)
+			if err != nil {
+				continue
+			}
+
+			candidate := &Path{Nodes: append(append([]string{}, last.Nodes[:i]...), spurPath.Nodes...)}
+			for _, edge := range rootEdges {
+				candidate.Edges = append(candidate.Edges, edge.clone())
+			}
+			candidate.Edges = append(candidate.Edges, spurPath.Edges...)
+			for _, edge := range candidate.Edges {
+				candidate.Weight += edge.Weight
+			}
+			if key := pathKey(candidate); !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+		sort.Slice(candidates, func(i, j int) bool { return lessPath(candidates[i], candidates[j]) })
+		accepted = append(accepted, candidates[0])
+		candidates = candidates[1:]
+	}
+	return accepted, nil
+}
+
+// lessPath orders paths by weight, then by number of edges, then by the IDs
+// of their edges.
+func lessPath(a, b *Path) bool {
+	if a.Weight != b.Weight {
+		return a.Weight < b.Weight
+	}
+	if len(a.Edges) != len(b.Edges) {
+		return len(a.Edges) < len(b.Edges)
+	}
+	return pathKey(a) < pathKey(b)
+}
+
+// pathKey identifies a path by the edges it follows.
+func pathKey(p *Path) string {
+	ids := make([]string, len(p.Edges))
+	for i, edge := range p.Edges {
+		ids[i] = edge.ID
+	}
+	return strings.Join(ids, "\x00")
+}
+
+func sameEdges(a, b []*Edge) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package graph
+
+import "testing"
+
+// newYenGraph builds the example graph from the description of Yen's algorithm
+func newYenGraph(t *testing.T) Graph {
+	t.Helper()
+	return buildGraph(t, nodesWithIDs("C", "D", "E", "F", "G", "H"), []*Edge{
+		{ID: "cd", From: "C", To: "D", Weight: 3},
+		{ID: "ce", From: "C", To: "E", Weight: 2},
+		{ID: "df", From: "D", To: "F", Weight: 4},
+		{ID: "ed", From: "E", To: "D", Weight: 1},
+		{ID: "ef", From: "E", To: "F", Weight: 2},
+		{ID: "eg", From: "E", To: "G", Weight: 3},
+		{ID: "fg", From: "F", To: "G", Weight: 2},
+		{ID: "fh", From: "F", To: "H", Weight: 1},
+		{ID: "gh", From: "G", To: "H", Weight: 2},
+	})
+}
+
+// TestKShortestPaths tests Yen's algorithm including its tie-breaking
+func TestKShortestPaths(t *testing.T) {
+	g := newYenGraph(t)
+
+	paths, err := g.KShortestPaths("C", "H", 4)
+	if err != nil {
+		t.Fatalf("KShortestPaths failed: %v", err)
+	}
+	want := []struct {
+		nodes  []string
+		weight float64
+	}{
+		{[]string{"C", "E", "F", "H"}, 5},
+		{[]string{"C", "E", "G", "H"}, 7},
+		// C-D-F-H and C-E-D-F-H both weigh 8; the one with fewer edges wins
+		{[]string{"C", "D", "F", "H"}, 8},
+		{[]string{"C", "E", "D", "F", "H"}, 8},
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("Expected %d paths, got %d", len(want), len(paths))
+	}
+	for i, w := range want {
+		if !equalIDs(paths[i].Nodes, w.nodes) || paths[i].Weight != w.weight {
+			t.Errorf("Expected path %d to be %v of weight %f, got %v of weight %f", i, w.nodes, w.weight, paths[i].Nodes, paths[i].Weight)
+		}
+		if len(paths[i].Edges) != len(paths[i].Nodes)-1 {
+			t.Errorf("Expected path %d to have %d edges, got %d", i, len(paths[i].Nodes)-1, len(paths[i].Edges))
+		}
+	}
+}
+
+// TestKShortestPathsExhaustive tests that asking for more paths than exist returns each loopless path once
+func TestKShortestPathsExhaustive(t *testing.T) {
+	g := newYenGraph(t)
+
+	paths, err := g.KShortestPaths("C", "H", 100)
+	if err != nil {
+		t.Fatalf("KShortestPaths failed: %v", err)
+	}
+	if len(paths) != 7 {
+		t.Errorf("Expected all 7 paths from C to H, got %d", len(paths))
+	}
+	seen := make(map[string]bool)
+	for i, p := range paths {
+		if i > 0 && p.Weight < paths[i-1].Weight {
+			t.Errorf("Expected paths in order of weight, got %f after %f", p.Weight, paths[i-1].Weight)
+		}
+		key := pathKey(p)
+		if seen[key] {
+			t.Errorf("Expected path %v only once", p.Nodes)
+		}
+		seen[key] = true
+		visited := make(map[string]bool)
+		for _, id := range p.Nodes {
+			if visited[id] {
+				t.Errorf("Expected loopless path, got %v", p.Nodes)
+			}
+			visited[id] = true
+		}
+	}
+
+	// Parallel edges make distinct paths
+	g.AddEdge(&Edge{ID: "fh2", From: "F", To: "H", Weight: 1})
+	paths, _ = g.KShortestPaths("C", "H", 2)
+	if len(paths) != 2 || paths[1].Weight != 5 || paths[1].Edges[2].ID != "fh2" {
+		t.Errorf("Expected the parallel edge to give a second path of weight 5, got %v", paths)
+	}
+
+	if _, err := g.KShortestPaths("H", "C", 3); err == nil {
+		t.Errorf("Expected error when there is no path")
+	}
+	if _, err := g.KShortestPaths("C", "H", 0); err == nil {
+		t.Errorf("Expected error for k of 0")
+	}
+}
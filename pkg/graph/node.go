@@ -1,6 +1,30 @@
 package graph
 
+import (
+	"maps"
+	"slices"
+)
+
 type Node struct {
 	ID         string
+	Labels     []string
 	Properties map[string]any
 }
+
+// HasLabel reports whether the node carries the given label.
+func (n *Node) HasLabel(label string) bool {
+	return slices.Contains(n.Labels, label)
+}
+
+// clone returns a copy of the node that shares no labels or properties with
+// it. Property values themselves are not copied.
+func (n *Node) clone() *Node {
+	return &Node{ID: n.ID, Labels: slices.Clone(n.Labels), Properties: maps.Clone(n.Properties)}
+}
+
+func cloneNodes(nodes []*Node) []*Node {
+	for i, node := range nodes {
+		nodes[i] = node.clone()
+	}
+	return nodes
+}
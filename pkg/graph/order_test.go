@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"cmp"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newDiamond builds A -> {B, C} -> D with edges added in reverse ID order
+func newDiamond(t *testing.T, opts ...Option) Graph {
+	t.Helper()
+	return buildGraph(t, nodesWithIDs("A", "B", "C", "D"), []*Edge{
+		{ID: "e4", From: "C", To: "D", Weight: 1.0},
+		{ID: "e3", From: "B", To: "D", Weight: 1.0},
+		{ID: "e2", From: "A", To: "C", Weight: 1.0},
+		{ID: "e1", From: "A", To: "B", Weight: 1.0},
+	}, opts...)
+}
+
+func edgeIDs(edges []*Edge) []string {
+	ids := make([]string, len(edges))
+	for i, edge := range edges {
+		ids[i] = edge.ID
+	}
+	return ids
+}
+
+// TestOrderByID tests that traversals and path searches follow edges by neighbor ID
+func TestOrderByID(t *testing.T) {
+	g := newDiamond(t)
+
+	// Map iteration order varies, so repeat to catch nondeterminism
+	for i := 0; i < 20; i++ {
+		neighbors, _ := g.Neighbors("A")
+		if got := edgeIDs(neighbors); !equalIDs(got, []string{"e1", "e2"}) {
+			t.Fatalf("Expected neighbors [e1 e2], got %v", got)
+		}
+		incoming, _ := g.Incoming("D")
+		if got := edgeIDs(incoming); !equalIDs(got, []string{"e3", "e4"}) {
+			t.Fatalf("Expected incoming [e3 e4], got %v", got)
+		}
+		dfs, _ := g.DFS("A")
+		if !equalIDs(dfs, []string{"A", "B", "D", "C"}) {
+			t.Fatalf("Expected DFS [A B D C], got %v", dfs)
+		}
+		bfs, _ := g.BFS("A")
+		if !equalIDs(bfs, []string{"A", "B", "C", "D"}) {
+			t.Fatalf("Expected BFS [A B C D], got %v", bfs)
+		}
+		path, _, _ := g.ShortestPath("A", "D")
+		if !equalIDs(path, []string{"A", "B", "D"}) {
+			t.Fatalf("Expected path [A B D], got %v", path)
+		}
+	}
+}
+
+// TestOrderByInsertion tests that edges follow insertion order, across rollbacks and persistence
+func TestOrderByInsertion(t *testing.T) {
+	g := newDiamond(t, WithOrdering(OrderByInsertion))
+
+	dfs, _ := g.DFS("A")
+	if !equalIDs(dfs, []string{"A", "C", "D", "B"}) {
+		t.Errorf("Expected DFS [A C D B], got %v", dfs)
+	}
+	path, _, _ := g.ShortestPath("A", "D")
+	if !equalIDs(path, []string{"A", "C", "D"}) {
+		t.Errorf("Expected path [A C D], got %v", path)
+	}
+
+	// A rolled back deletion puts the edge back in its original place
+	tx := g.Begin()
+	tx.DeleteEdgeByID("e2")
+	tx.Rollback()
+	neighbors, _ := g.Neighbors("A")
+	if got := edgeIDs(neighbors); !equalIDs(got, []string{"e2", "e1"}) {
+		t.Errorf("Expected neighbors [e2 e1] after rollback, got %v", got)
+	}
+
+	persister := &BoltPersist{Path: filepath.Join(t.TempDir(), "graph.db")}
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	loaded, err := persister.Load()
+	if err != nil {
+		t.Fatalf("Failed to load graph: %v", err)
+	}
+	incoming, _ := loaded.Incoming("D")
+	if got := edgeIDs(incoming); !equalIDs(got, []string{"e4", "e3"}) {
+		t.Errorf("Expected incoming [e4 e3] after load, got %v", got)
+	}
+}
+
+// TestOrderByComparator tests ordering edges with a user comparator
+func TestOrderByComparator(t *testing.T) {
+	byTypeDesc := func(a, b *Edge) int { return strings.Compare(b.Type, a.Type) }
+	g := NewGraph(WithEdgeComparator(byTypeDesc))
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(&Node{ID: id})
+	}
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Type: "knows"})
+	g.AddEdge(&Edge{ID: "e2", From: "A", To: "C", Type: "likes"})
+	g.AddEdge(&Edge{ID: "e3", From: "A", To: "D", Type: "likes"})
+
+	neighbors, _ := g.Neighbors("A")
+	if got := edgeIDs(neighbors); !equalIDs(got, []string{"e2", "e3", "e1"}) {
+		t.Errorf("Expected neighbors [e2 e3 e1], got %v", got)
+	}
+
+	if _, err := New(WithOrdering(OrderByComparator)); err == nil {
+		t.Errorf("Expected error when ordering by comparator without one")
+	}
+}
+
+// TestOrderAfterChanges tests that edges stay in order as they are updated and
+// removed, and that a snapshot keeps the order it was taken with
+func TestOrderAfterChanges(t *testing.T) {
+	byWeight := func(a, b *Edge) int { return cmp.Compare(a.Weight, b.Weight) }
+	g := newDiamond(t, WithEdgeComparator(byWeight))
+	g.AddNode(&Node{ID: "E"})
+	g.AddEdge(&Edge{ID: "e5", From: "A", To: "E", Weight: 2.0})
+
+	neighbors, _ := g.Neighbors("A")
+	if got := edgeIDs(neighbors); !equalIDs(got, []string{"e1", "e2", "e5"}) {
+		t.Fatalf("Expected neighbors [e1 e2 e5], got %v", got)
+	}
+
+	snap := g.Snapshot()
+	if err := g.SetEdgeWeight("e1", 3.0); err != nil {
+		t.Fatalf("SetEdgeWeight failed: %v", err)
+	}
+	neighbors, _ = g.Neighbors("A")
+	if got := edgeIDs(neighbors); !equalIDs(got, []string{"e2", "e5", "e1"}) {
+		t.Errorf("Expected neighbors [e2 e5 e1] after update, got %v", got)
+	}
+	neighbors, _ = snap.Neighbors("A")
+	if got := edgeIDs(neighbors); !equalIDs(got, []string{"e1", "e2", "e5"}) {
+		t.Errorf("Expected snapshot neighbors [e1 e2 e5], got %v", got)
+	}
+
+	g.DeleteEdgeByID("e5")
+	neighbors, _ = g.Neighbors("A")
+	if got := edgeIDs(neighbors); !equalIDs(got, []string{"e2", "e1"}) {
+		t.Errorf("Expected neighbors [e2 e1] after deletion, got %v", got)
+	}
+	traversed, _ := g.Traverse("D", TraversalOptions{Direction: BothDirections})
+	if len(traversed) != 4 {
+		t.Errorf("Expected to traverse 4 nodes both ways from D, got %v", traversed)
+	}
+}
@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+
+	pq "github.com/camwhite18/ArachneGraph/pkg/priority_queue"
+)
+
+// Path is a walk through the graph. Nodes holds the IDs of the nodes visited
+// in order, Edges the edges followed between them, one fewer than the nodes,
+// and Weight the sum of the edges' weights.
+type Path struct {
+	Nodes  []string
+	Edges  []*Edge
+	Weight float64
+}
+
+// PathTree holds the shortest paths from a source to every node reachable
+// from it. Distances maps each reachable node to the weight of its shortest
+// path, and Predecessors maps each reachable node other than the source to
+// the last edge of that path.
+type PathTree struct {
+	Source       string
+	Distances    map[string]float64
+	Predecessors map[string]*Edge
+}
+
+// PathTo extracts the shortest path from the tree's source to a node.
+func (t *PathTree) PathTo(id string) (*Path, error) {
+	d, reached := t.Distances[id]
+	if !reached {
+		return nil, fmt.Errorf("no path found from %q to %q", t.Source, id)
+	}
+	path := &Path{Nodes: []string{id}, Weight: d}
+	for current := id; current != t.Source; {
+		edge := t.Predecessors[current]
+		current = edge.Other(current)
+		path.Nodes = append(path.Nodes, current)
+		path.Edges = append(path.Edges, edge.clone())
+	}
+	reverse(path.Nodes)
+	reverse(path.Edges)
+	return path, nil
+}
+
+// newPathTree returns a tree holding only its source.
+func newPathTree(source string) *PathTree {
+	return &PathTree{
+		Source:       source,
+		Distances:    map[string]float64{source: 0},
+		Predecessors: make(map[string]*Edge),
+	}
+}
+
+// clone returns a copy of the tree whose edges may be handed to callers.
+func (t *PathTree) clone() *PathTree {
+	c := &PathTree{
+		Source:       t.Source,
+		Distances:    t.Distances,
+		Predecessors: make(map[string]*Edge, len(t.Predecessors)),
+	}
+	for id, edge := range t.Predecessors {
+		c.Predecessors[id] = edge.clone()
+	}
+	return c
+}
+
+// dijkstra computes the shortest paths from source to every node reachable
+// from it with Dijkstra's algorithm, or stops once it settles target if that
+// is not empty. An edge followed away from a node weighs what weight returns
+// for it, and is skipped if weight returns false. The weights must not be
+// negative.
+func (s *store) dijkstra(search *search, source, target string, weight func(edge *Edge, from string) (float64, bool)) (*PathTree, error) {
+	tree := newPathTree(source)
+	priorityQueue := make(pq.PriorityQueue, 0)
+	heap.Push(&priorityQueue, &pq.PriorityQueueItem{NodeID: source, Distance: 0})
+	settled := make(map[string]bool)
+
+	for priorityQueue.Len() > 0 {
+		current := heap.Pop(&priorityQueue).(*pq.PriorityQueueItem).NodeID
+		if settled[current] {
+			continue
+		}
+		if err := search.visit(); err != nil {
+			return nil, err
+		}
+		settled[current] = true
+		if current == target {
+			break
+		}
+
+		for _, edge := range s.adjacent(s.out.m[current]) {
+			neighbor := edge.Other(current)
+			if settled[neighbor] {
+				continue
+			}
+			w, ok := weight(edge, current)
+			if !ok {
+				continue
+			}
+			alt := tree.Distances[current] + w
+			if d, reached := tree.Distances[neighbor]; !reached || alt < d {
+				tree.Distances[neighbor] = alt
+				tree.Predecessors[neighbor] = edge
+				heap.Push(&priorityQueue, &pq.PriorityQueueItem{NodeID: neighbor, Distance: alt})
+			}
+		}
+	}
+	return tree, nil
+}
+
+// ShortestPathTree computes the shortest paths from source to every node
+// reachable from it with a single run of Dijkstra's algorithm. Like
+// ShortestPath, it fails with ErrNegativeWeight if any edge has a negative
+// weight.
+func (s *store) ShortestPathTree(source string) (*PathTree, error) {
+	return s.ShortestPathTreeContext(context.Background(), source)
+}
+
+// ShortestPathTreeContext is ShortestPathTree that gives up with the context's
+// error once the context is done, or with ErrVisitLimit once it settles more
+// nodes than allowed.
+func (s *store) ShortestPathTreeContext(ctx context.Context, source string) (*PathTree, error) {
+	if _, exists := s.nodes.m[source]; !exists {
+		return nil, fmt.Errorf("source node %q not found", source)
+	}
+	if s.negative > 0 {
+		return nil, ErrNegativeWeight
+	}
+	tree, err := s.dijkstra(s.newSearch(ctx), source, "", edgeWeight)
+	if err != nil {
+		return nil, err
+	}
+	return tree.clone(), nil
+}
+
+// edgeWeight weighs edges for dijkstra by their own weight.
+func edgeWeight(edge *Edge, _ string) (float64, bool) {
+	return edge.Weight, true
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestShortestPathTree tests that one tree answers every destination like ShortestPath
+func TestShortestPathTree(t *testing.T) {
+	g := newGrid(t, 6)
+
+	tree, err := g.ShortestPathTree("0,0")
+	if err != nil {
+		t.Fatalf("ShortestPathTree failed: %v", err)
+	}
+	if len(tree.Distances) != 36 {
+		t.Errorf("Expected distances to 36 nodes, got %d", len(tree.Distances))
+	}
+	for id, d := range tree.Distances {
+		_, want, err := g.ShortestPath("0,0", id)
+		if err != nil {
+			t.Fatalf("ShortestPath failed: %v", err)
+		}
+		if d != want {
+			t.Errorf("Expected distance %f to %s, got %f", want, id, d)
+		}
+
+		path, err := tree.PathTo(id)
+		if err != nil {
+			t.Fatalf("Failed to extract path to %s: %v", id, err)
+		}
+		if path.Nodes[0] != "0,0" || path.Nodes[len(path.Nodes)-1] != id || len(path.Edges) != len(path.Nodes)-1 {
+			t.Fatalf("Expected a path from 0,0 to %s, got %v", id, path.Nodes)
+		}
+		sum := 0.0
+		for i, edge := range path.Edges {
+			if edge.From != path.Nodes[i] || edge.To != path.Nodes[i+1] {
+				t.Errorf("Expected edge %d to lead from %s to %s", i, path.Nodes[i], path.Nodes[i+1])
+			}
+			sum += edge.Weight
+		}
+		if sum != path.Weight || path.Weight != d {
+			t.Errorf("Expected path weight %f to %s, got %f summing to %f", d, id, path.Weight, sum)
+		}
+	}
+
+	if path, err := tree.PathTo("0,0"); err != nil || len(path.Nodes) != 1 || len(path.Edges) != 0 {
+		t.Errorf("Expected an empty path to the source, got %v: %v", path, err)
+	}
+}
+
+// TestShortestPathTreeUnreachable tests unreachable nodes and negative weights
+func TestShortestPathTreeUnreachable(t *testing.T) {
+	g := newDiamond(t)
+
+	tree, err := g.ShortestPathTree("B")
+	if err != nil {
+		t.Fatalf("ShortestPathTree failed: %v", err)
+	}
+	if _, err := tree.PathTo("A"); err == nil {
+		t.Errorf("Expected error for a path to an unreachable node")
+	}
+	if _, err := g.ShortestPathTree("nowhere"); err == nil {
+		t.Errorf("Expected error for a missing source")
+	}
+
+	// Trees from Bellman-Ford extract paths the same way
+	negative := newArbitrageGraph(t)
+	if _, err := negative.ShortestPathTree("A"); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Expected ErrNegativeWeight, got %v", err)
+	}
+	tree, _ = negative.BellmanFord("A")
+	path, err := tree.PathTo("D")
+	if err != nil {
+		t.Fatalf("Failed to extract path: %v", err)
+	}
+	if !equalIDs(path.Nodes, []string{"A", "B", "C", "D"}) || path.Weight != 2.0 {
+		t.Errorf("Expected path [A B C D] of weight 2, got %v of weight %f", path.Nodes, path.Weight)
+	}
+}
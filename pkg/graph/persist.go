@@ -1,6 +1,10 @@
 package graph
 
+// Persister saves graphs to and loads them from durable storage. Load applies
+// its options on top of the configuration the graph was saved with; options
+// that cannot be saved, such as WithIDGenerator and WithPersister, must be
+// passed again.
 type Persister interface {
-	Save(g *Graph) error
-	Load() (*Graph, error)
+	Save(g Reader) error
+	Load(opts ...Option) (Graph, error)
 }
@@ -1,8 +1,10 @@
 package graph
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	bolt "go.etcd.io/bbolt"
 )
@@ -11,7 +13,21 @@ type BoltPersist struct {
 	Path string
 }
 
-func (bp *BoltPersist) Save(g Graph) error {
+// savedEdge is the form edges are saved in. Seq keeps the edges' insertion
+// order, which the edges bucket, being sorted by ID, loses.
+type savedEdge struct {
+	*Edge
+	Seq uint64 `json:",omitempty"`
+}
+
+// Save writes the graph to the database, replacing its previous contents. A
+// live graph is saved from a snapshot, so that concurrent writes cannot leave
+// the nodes, edges and schema saved out of step with each other.
+func (bp *BoltPersist) Save(g Reader) error {
+	if live, ok := g.(Graph); ok {
+		g = live.Snapshot()
+	}
+
 	db, err := bolt.Open(bp.Path, 0600, nil)
 	if err != nil {
 		return fmt.Errorf("could not open bolt database: %v", err)
@@ -19,16 +35,48 @@ func (bp *BoltPersist) Save(g Graph) error {
 	defer db.Close()
 
 	return db.Update(func(tx *bolt.Tx) error {
-		nodes, err := tx.CreateBucketIfNotExists([]byte("nodes"))
+		// Start from empty buckets so that elements deleted since the last
+		// save do not linger.
+		for _, name := range []string{"meta", "nodes", "edges", "indexes", "constraints"} {
+			if err := tx.DeleteBucket([]byte(name)); err != nil && err != bolt.ErrBucketNotFound {
+				return fmt.Errorf("could not clear %s bucket: %v", name, err)
+			}
+		}
+		meta, err := tx.CreateBucket([]byte("meta"))
+		if err != nil {
+			return fmt.Errorf("could not create meta bucket: %v", err)
+		}
+		nodes, err := tx.CreateBucket([]byte("nodes"))
 		if err != nil {
 			return fmt.Errorf("could not create nodes bucket: %v", err)
 		}
-		edges, err := tx.CreateBucketIfNotExists([]byte("edges"))
+		edges, err := tx.CreateBucket([]byte("edges"))
 		if err != nil {
 			return fmt.Errorf("could not create edges bucket: %v", err)
 		}
+		indexes, err := tx.CreateBucket([]byte("indexes"))
+		if err != nil {
+			return fmt.Errorf("could not create indexes bucket: %v", err)
+		}
+		constraints, err := tx.CreateBucket([]byte("constraints"))
+		if err != nil {
+			return fmt.Errorf("could not create constraints bucket: %v", err)
+		}
+
+		cfg, err := json.Marshal(g.Config())
+		if err != nil {
+			return fmt.Errorf("could not serialize config: %v", err)
+		}
+		if err := meta.Put([]byte("config"), cfg); err != nil {
+			return fmt.Errorf("could not serialize config: %v", err)
+		}
+		// The structure hash tells a saved contraction hierarchy whether it
+		// was built from this graph.
+		if err := meta.Put([]byte("structure"), binary.BigEndian.AppendUint64(nil, structureHash(g))); err != nil {
+			return fmt.Errorf("could not serialize structure hash: %v", err)
+		}
 
-		for id, node := range g.Nodes() {
+		for id, node := range g.AllNodes() {
 			data, err := json.Marshal(node)
 			if err != nil {
 				return fmt.Errorf("could not serialize node %v: %v", node, err)
@@ -37,24 +85,47 @@ func (bp *BoltPersist) Save(g Graph) error {
 				return fmt.Errorf("could not serialize node %v: %v", node, err)
 			}
 		}
-		for from, outMap := range g.OutEdges() {
-			for to, edge := range outMap {
-				b, err := json.Marshal(edge)
-				if err != nil {
-					return fmt.Errorf("could not serialize edge %v: %v", edge, err)
-				}
-				key := []byte(from + "->" + to)
-				if err := edges.Put(key, b); err != nil {
-					return fmt.Errorf("could not serialize edge %v: %v", edge, err)
-				}
+		// Edges are keyed by ID so that parallel edges between the same
+		// pair of nodes are all kept, and undirected edges are stored once.
+		for id, edge := range g.AllEdges() {
+			b, err := json.Marshal(savedEdge{Edge: edge, Seq: edge.seq})
+			if err != nil {
+				return fmt.Errorf("could not serialize edge %v: %v", edge, err)
+			}
+			if err := edges.Put([]byte(id), b); err != nil {
+				return fmt.Errorf("could not serialize edge %v: %v", edge, err)
+			}
+		}
+		// Only index definitions are stored; the indexes themselves are
+		// rebuilt from the nodes on load.
+		for _, def := range g.Indexes() {
+			b, err := json.Marshal(def)
+			if err != nil {
+				return fmt.Errorf("could not serialize index %v: %v", def, err)
+			}
+			key := []byte(def.Label + "\x00" + def.Property)
+			if err := indexes.Put(key, b); err != nil {
+				return fmt.Errorf("could not serialize index %v: %v", def, err)
+			}
+		}
+		for i, c := range g.Constraints() {
+			b, err := json.Marshal(c)
+			if err != nil {
+				return fmt.Errorf("could not serialize constraint %v: %v", c, err)
+			}
+			key := []byte(fmt.Sprintf("%08d", i))
+			if err := constraints.Put(key, b); err != nil {
+				return fmt.Errorf("could not serialize constraint %v: %v", c, err)
 			}
 		}
 		return nil
 	})
 }
 
-func (bp *BoltPersist) Load() (Graph, error) {
-	g := NewGraph()
+// Load reads the graph saved in the database, applying opts on top of the
+// configuration it was saved with.
+func (bp *BoltPersist) Load(opts ...Option) (Graph, error) {
+	var g Graph
 	db, err := bolt.Open(bp.Path, 0600, nil)
 	if err != nil {
 		return nil, fmt.Errorf("could not open bolt database: %v", err)
@@ -62,6 +133,22 @@ func (bp *BoltPersist) Load() (Graph, error) {
 	defer db.Close()
 
 	err = db.View(func(tx *bolt.Tx) error {
+		// Databases written before the config was persisted have no meta
+		// bucket and hold graphs with the default config.
+		cfg := defaultConfig()
+		if meta := tx.Bucket([]byte("meta")); meta != nil {
+			if data := meta.Get([]byte("config")); data != nil {
+				if err := json.Unmarshal(data, &cfg); err != nil {
+					return fmt.Errorf("could not deserialize config: %v", err)
+				}
+			}
+		}
+		var err error
+		g, err = New(append([]Option{withConfig(cfg)}, opts...)...)
+		if err != nil {
+			return err
+		}
+
 		nodes := tx.Bucket([]byte("nodes"))
 		if nodes == nil {
 			return fmt.Errorf("nodes bucket does not exist")
@@ -71,7 +158,7 @@ func (bp *BoltPersist) Load() (Graph, error) {
 			return fmt.Errorf("edges bucket does not exist")
 		}
 
-		err := nodes.ForEach(func(k, v []byte) error {
+		err = nodes.ForEach(func(k, v []byte) error {
 			var node Node
 			if err := json.Unmarshal(v, &node); err != nil {
 				return fmt.Errorf("could not deserialize node %s: %v", k, err)
@@ -85,21 +172,139 @@ func (bp *BoltPersist) Load() (Graph, error) {
 			return fmt.Errorf("could not deserialize nodes bucket: %v", err)
 		}
 
+		// Databases written before the meta bucket keyed each edge by
+		// "from->to" and did not need edge IDs to be unique. The key becomes
+		// the ID of an edge saved without one or with one already taken.
+		baseline := tx.Bucket([]byte("meta")) == nil
+		taken := make(map[string]bool)
+		var saved []savedEdge
 		err = edges.ForEach(func(k, v []byte) error {
-			var edge Edge
+			var edge savedEdge
 			if err := json.Unmarshal(v, &edge); err != nil {
 				return fmt.Errorf("could not deserialize edge %s: %v", k, err)
 			}
-			if err := g.AddEdge(&edge); err != nil {
-				return fmt.Errorf("could not add edge %v: %v", edge, err)
+			if edge.Edge != nil && baseline && (edge.ID == "" || taken[edge.ID]) {
+				edge.ID = string(k)
 			}
+			if edge.Edge != nil {
+				taken[edge.ID] = true
+			}
+			saved = append(saved, edge)
 			return nil
 		})
 		if err != nil {
 			return fmt.Errorf("could not deserialize edges bucket: %v", err)
 		}
+		// Adding the edges in their saved order restores the insertion
+		// order. Edges saved without one keep the bucket's order.
+		sort.SliceStable(saved, func(i, j int) bool { return saved[i].Seq < saved[j].Seq })
+		for _, edge := range saved {
+			if err := g.AddEdge(edge.Edge); err != nil {
+				return fmt.Errorf("could not add edge %v: %v", *edge.Edge, err)
+			}
+		}
+
+		// Databases written before indexes were persisted have no
+		// indexes bucket.
+		if indexes := tx.Bucket([]byte("indexes")); indexes != nil {
+			err = indexes.ForEach(func(k, v []byte) error {
+				var def IndexDefinition
+				if err := json.Unmarshal(v, &def); err != nil {
+					return fmt.Errorf("could not deserialize index %q: %v", k, err)
+				}
+				if err := g.CreateIndex(def.Label, def.Property, def.Kind); err != nil {
+					return fmt.Errorf("could not create index %v: %v", def, err)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("could not deserialize indexes bucket: %v", err)
+			}
+		}
+		// Constraints are declared after the data is loaded, which also
+		// revalidates it.
+		if constraints := tx.Bucket([]byte("constraints")); constraints != nil {
+			err = constraints.ForEach(func(k, v []byte) error {
+				var c Constraint
+				if err := json.Unmarshal(v, &c); err != nil {
+					return fmt.Errorf("could not deserialize constraint %s: %v", k, err)
+				}
+				if err := g.CreateConstraint(c); err != nil {
+					return fmt.Errorf("could not create constraint %v: %v", c, err)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("could not deserialize constraints bucket: %v", err)
+			}
+		}
 
 		return nil
 	})
 	return g, err
 }
+
+// SaveContractionHierarchy writes a contraction hierarchy to the database,
+// replacing any saved before. It is kept apart from the graph, which Save
+// leaves it alongside; it is up to the caller to save a new hierarchy when the
+// graph changes. Until they do, LoadContractionHierarchy refuses to load the
+// old one.
+func (bp *BoltPersist) SaveContractionHierarchy(ch *ContractionHierarchy) error {
+	data, err := json.Marshal(ch)
+	if err != nil {
+		return fmt.Errorf("could not serialize contraction hierarchy: %v", err)
+	}
+
+	db, err := bolt.Open(bp.Path, 0600, nil)
+	if err != nil {
+		return fmt.Errorf("could not open bolt database: %v", err)
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		hierarchy, err := tx.CreateBucketIfNotExists([]byte("hierarchy"))
+		if err != nil {
+			return fmt.Errorf("could not create hierarchy bucket: %v", err)
+		}
+		if err := hierarchy.Put([]byte("ch"), data); err != nil {
+			return fmt.Errorf("could not serialize contraction hierarchy: %v", err)
+		}
+		return nil
+	})
+}
+
+// LoadContractionHierarchy reads the contraction hierarchy saved in the
+// database. It fails if the graph saved alongside has changed since the
+// hierarchy was built.
+func (bp *BoltPersist) LoadContractionHierarchy() (*ContractionHierarchy, error) {
+	db, err := bolt.Open(bp.Path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not open bolt database: %v", err)
+	}
+	defer db.Close()
+
+	ch := &ContractionHierarchy{}
+	err = db.View(func(tx *bolt.Tx) error {
+		hierarchy := tx.Bucket([]byte("hierarchy"))
+		if hierarchy == nil {
+			return fmt.Errorf("hierarchy bucket does not exist")
+		}
+		data := hierarchy.Get([]byte("ch"))
+		if data == nil {
+			return fmt.Errorf("no contraction hierarchy saved")
+		}
+		if err := json.Unmarshal(data, ch); err != nil {
+			return fmt.Errorf("could not deserialize contraction hierarchy: %v", err)
+		}
+		if meta := tx.Bucket([]byte("meta")); meta != nil {
+			if data := meta.Get([]byte("structure")); len(data) == 8 && binary.BigEndian.Uint64(data) != ch.graph {
+				return fmt.Errorf("contraction hierarchy was built from a different graph than the one saved")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
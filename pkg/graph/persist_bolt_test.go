@@ -0,0 +1,205 @@
+package graph
+
+import (
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// TestBoltPersistParallelEdges tests that parallel edges survive a save and load
+func TestBoltPersistParallelEdges(t *testing.T) {
+	persister := &BoltPersist{Path: filepath.Join(t.TempDir(), "graph.db")}
+
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e2", From: "A", To: "B", Weight: 2.0})
+
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	loaded, err := persister.Load()
+	if err != nil {
+		t.Fatalf("Failed to load graph: %v", err)
+	}
+	edges, err := loaded.EdgesBetween("A", "B")
+	if err != nil {
+		t.Fatalf("Failed to get edges between nodes: %v", err)
+	}
+	if len(edges) != 2 {
+		t.Errorf("Expected 2 parallel edges, got %d", len(edges))
+	}
+}
+
+// TestBoltPersistOverwrite tests that saving again drops deleted elements
+func TestBoltPersistOverwrite(t *testing.T) {
+	persister := &BoltPersist{Path: filepath.Join(t.TempDir(), "graph.db")}
+
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 1.0})
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	g.DeleteNode("B")
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	loaded, err := persister.Load()
+	if err != nil {
+		t.Fatalf("Failed to load graph: %v", err)
+	}
+	if _, err := loaded.GetNode("B"); err == nil {
+		t.Error("Expected deleted node to be absent after reload")
+	}
+	if _, err := loaded.GetEdgeByID("e1"); err == nil {
+		t.Error("Expected deleted edge to be absent after reload")
+	}
+}
+
+// TestBoltPersistLabelsAndTypes tests that labels and edge types are reindexed
+// on load
+func TestBoltPersistLabelsAndTypes(t *testing.T) {
+	persister := &BoltPersist{Path: filepath.Join(t.TempDir(), "graph.db")}
+
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A", Labels: []string{"Person"}})
+	g.AddNode(&Node{ID: "B", Labels: []string{"Person"}})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Type: "KNOWS"})
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	loaded, err := persister.Load()
+	if err != nil {
+		t.Fatalf("Failed to load graph: %v", err)
+	}
+	if people := loaded.NodesByLabel("Person"); len(people) != 2 {
+		t.Errorf("Expected 2 people after reload, got %d", len(people))
+	}
+	if knows := loaded.EdgesByType("KNOWS"); len(knows) != 1 {
+		t.Errorf("Expected 1 KNOWS edge after reload, got %d", len(knows))
+	}
+}
+
+// TestBoltPersistIndexes tests that index definitions are persisted and the
+// indexes rebuilt on load
+func TestBoltPersistIndexes(t *testing.T) {
+	persister := &BoltPersist{Path: filepath.Join(t.TempDir(), "graph.db")}
+
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"age": 30}})
+	g.AddNode(&Node{ID: "B", Labels: []string{"Person"}, Properties: map[string]any{"age": 40}})
+	g.CreateIndex("Person", "age", OrderedIndex)
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	loaded, err := persister.Load()
+	if err != nil {
+		t.Fatalf("Failed to load graph: %v", err)
+	}
+	defs := loaded.Indexes()
+	if len(defs) != 1 || defs[0] != (IndexDefinition{Label: "Person", Property: "age", Kind: OrderedIndex}) {
+		t.Fatalf("Expected ordered index on Person.age, got %v", defs)
+	}
+	nodes, err := loaded.FindNodesInRange("Person", "age", 35, nil)
+	if err != nil {
+		t.Fatalf("FindNodesInRange failed: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].ID != "B" {
+		t.Errorf("Expected [B], got %v", nodes)
+	}
+}
+
+// TestBoltPersistConstraints tests that constraints are persisted and enforced
+// after load
+func TestBoltPersistConstraints(t *testing.T) {
+	persister := &BoltPersist{Path: filepath.Join(t.TempDir(), "graph.db")}
+
+	g := NewGraph()
+	g.CreateConstraint(Constraint{Kind: UniqueConstraint, Label: "Person", Property: "email"})
+	g.CreateConstraint(Constraint{Kind: TypeConstraint, Label: "Person", Property: "age", Type: NumberProperty})
+	g.AddNode(&Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"email": "a@example.com"}})
+	if err := persister.Save(g); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	loaded, err := persister.Load()
+	if err != nil {
+		t.Fatalf("Failed to load graph: %v", err)
+	}
+	if got := loaded.Constraints(); len(got) != 2 {
+		t.Fatalf("Expected 2 constraints after reload, got %v", got)
+	}
+	err = loaded.AddNode(&Node{ID: "B", Labels: []string{"Person"}, Properties: map[string]any{"email": "a@example.com"}})
+	if err == nil {
+		t.Error("Expected unique constraint to be enforced after reload")
+	}
+}
+
+// TestBoltPersistBaselineFormat tests loading a database written before edges
+// had unique IDs, whose edges are keyed by "from->to"
+func TestBoltPersistBaselineFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.db")
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		nodes, err := tx.CreateBucket([]byte("nodes"))
+		if err != nil {
+			return err
+		}
+		for _, id := range []string{"A", "B", "C", "D"} {
+			if err := nodes.Put([]byte(id), []byte(`{"ID":"`+id+`","Properties":null}`)); err != nil {
+				return err
+			}
+		}
+		edges, err := tx.CreateBucket([]byte("edges"))
+		if err != nil {
+			return err
+		}
+		saved := map[string]string{
+			"A->B": `{"ID":"","From":"A","To":"B","Weight":2.5,"Properties":null}`,
+			"B->C": `{"ID":"road","From":"B","To":"C","Weight":1,"Properties":null}`,
+			"C->D": `{"ID":"road","From":"C","To":"D","Weight":1,"Properties":null}`,
+		}
+		for key, edge := range saved {
+			if err := edges.Put([]byte(key), []byte(edge)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to write database: %v", err)
+	}
+
+	loaded, err := (&BoltPersist{Path: path}).Load()
+	if err != nil {
+		t.Fatalf("Failed to load graph: %v", err)
+	}
+	edge, err := loaded.GetEdgeByID("A->B")
+	if err != nil {
+		t.Fatalf("Expected edge keyed by its endpoints: %v", err)
+	}
+	if edge.From != "A" || edge.To != "B" || edge.Weight != 2.5 {
+		t.Errorf("Expected edge A->B with weight 2.5, got %+v", edge)
+	}
+
+	// Of two edges sharing an ID, the first in the bucket keeps it
+	if edge, err := loaded.GetEdgeByID("road"); err != nil || edge.From != "B" {
+		t.Errorf("Expected edge road from B, got %+v (%v)", edge, err)
+	}
+	if edge, err := loaded.GetEdgeByID("C->D"); err != nil || edge.From != "C" {
+		t.Errorf("Expected edge C->D from C, got %+v (%v)", edge, err)
+	}
+}
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrVisitLimit is returned by an algorithm that would visit more nodes than
+// the limit set with WithVisitLimit.
+var ErrVisitLimit = errors.New("visit limit exceeded")
+
+// cancelCheckInterval is how many visits an algorithm makes between checks of
+// its context, which are too costly to make on every visit.
+const cancelCheckInterval = 256
+
+// search tracks the cancellation and visit limit of one run of an algorithm.
+type search struct {
+	ctx     context.Context
+	limit   int
+	visited int
+	steps   int
+}
+
+func (s *store) newSearch(ctx context.Context) *search {
+	return &search{ctx: ctx, limit: s.cfg.VisitLimit}
+}
+
+// visit counts a visited node, returning an error if the algorithm must stop.
+func (s *search) visit() error {
+	s.visited++
+	if s.limit > 0 && s.visited > s.limit {
+		return ErrVisitLimit
+	}
+	if s.visited%cancelCheckInterval == 1 {
+		return s.ctx.Err()
+	}
+	return nil
+}
+
+// step counts a unit of work other than a visit, such as relaxing an edge,
+// checking the context as often as visit does.
+func (s *search) step() error {
+	s.steps++
+	if s.steps%cancelCheckInterval == 1 {
+		return s.ctx.Err()
+	}
+	return nil
+}
+
+// unlimited returns a search sharing the context but not the visit limit,
+// for the inner runs of an algorithm that counts its visits itself.
+func (s *search) unlimited() *search {
+	return &search{ctx: s.ctx}
+}
@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// newChain builds a path graph n0 -> n1 -> ... of n nodes
+func newChain(t *testing.T, n int, opts ...Option) Graph {
+	t.Helper()
+	var nodes []*Node
+	var edges []*Edge
+	for i := 0; i < n; i++ {
+		nodes = append(nodes, &Node{ID: fmt.Sprintf("n%d", i)})
+		if i > 0 {
+			edges = append(edges, &Edge{ID: fmt.Sprintf("e%d", i), From: fmt.Sprintf("n%d", i-1), To: fmt.Sprintf("n%d", i), Weight: 1.0})
+		}
+	}
+	return buildGraph(t, nodes, edges, opts...)
+}
+
+// TestAlgorithmsCancelled tests that every algorithm returns the error of a done context
+func TestAlgorithmsCancelled(t *testing.T) {
+	g := newChain(t, 1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := g.DFSContext(ctx, "n0"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected DFS to be cancelled, got %v", err)
+	}
+	if _, err := g.BFSContext(ctx, "n0"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected BFS to be cancelled, got %v", err)
+	}
+	if _, _, err := g.ShortestPathContext(ctx, "n0", "n999"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ShortestPath to be cancelled, got %v", err)
+	}
+	if _, err := g.TraverseContext(ctx, "n0", TraversalOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Traverse to be cancelled, got %v", err)
+	}
+
+	expired, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-expired.Done()
+	if _, err := g.Snapshot().BFSContext(expired, "n0"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected BFS on a snapshot to time out, got %v", err)
+	}
+
+	// A live context lets the algorithms finish
+	result, err := g.DFSContext(context.Background(), "n0")
+	if err != nil {
+		t.Fatalf("DFS failed: %v", err)
+	}
+	if len(result) != 1000 {
+		t.Errorf("Expected DFS to visit 1000 nodes, got %d", len(result))
+	}
+}
+
+// TestVisitLimit tests that algorithms give up after visiting too many nodes
+func TestVisitLimit(t *testing.T) {
+	g := newChain(t, 10, WithVisitLimit(5))
+
+	if _, err := g.DFS("n0"); !errors.Is(err, ErrVisitLimit) {
+		t.Errorf("Expected DFS to hit the visit limit, got %v", err)
+	}
+	if _, err := g.BFS("n0"); !errors.Is(err, ErrVisitLimit) {
+		t.Errorf("Expected BFS to hit the visit limit, got %v", err)
+	}
+	if _, _, err := g.ShortestPath("n0", "n9"); !errors.Is(err, ErrVisitLimit) {
+		t.Errorf("Expected ShortestPath to hit the visit limit, got %v", err)
+	}
+	if _, err := g.Traverse("n0", TraversalOptions{}); !errors.Is(err, ErrVisitLimit) {
+		t.Errorf("Expected Traverse to hit the visit limit, got %v", err)
+	}
+
+	// Searches within the limit are unaffected
+	path, _, err := g.ShortestPath("n0", "n4")
+	if err != nil {
+		t.Fatalf("ShortestPath failed: %v", err)
+	}
+	if len(path) != 5 {
+		t.Errorf("Expected a path of 5 nodes, got %v", path)
+	}
+	if visits, err := g.Traverse("n0", TraversalOptions{MaxDepth: 4}); err != nil || len(visits) != 5 {
+		t.Errorf("Expected 5 visits within the limit, got %d: %v", len(visits), err)
+	}
+
+	if _, err := New(WithVisitLimit(-1)); err == nil {
+		t.Errorf("Expected error for a negative visit limit")
+	}
+}
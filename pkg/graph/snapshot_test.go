@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestSnapshotIsolation tests that a snapshot is unaffected by later writes
+func TestSnapshotIsolation(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"age": 30}})
+	g.AddNode(&Node{ID: "B", Labels: []string{"Person"}, Properties: map[string]any{"age": 40}})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Type: "KNOWS", Weight: 1.0})
+	g.CreateIndex("Person", "age", OrderedIndex)
+
+	snap := g.Snapshot()
+
+	g.AddNode(&Node{ID: "C", Labels: []string{"Person"}, Properties: map[string]any{"age": 35}})
+	g.AddEdge(&Edge{ID: "e2", From: "B", To: "C", Type: "KNOWS", Weight: 1.0})
+	g.DeleteEdgeByID("e1")
+	g.DeleteNode("A")
+	g.CreateConstraint(Constraint{Kind: UniqueConstraint, Label: "Person", Property: "age"})
+
+	if _, err := snap.GetNode("A"); err != nil {
+		t.Errorf("Expected snapshot to keep deleted node: %v", err)
+	}
+	if _, err := snap.GetNode("C"); err == nil {
+		t.Error("Expected snapshot not to see a later node")
+	}
+	if _, err := snap.GetEdgeByID("e1"); err != nil {
+		t.Errorf("Expected snapshot to keep deleted edge: %v", err)
+	}
+	if knows := snap.EdgesByType("KNOWS"); len(knows) != 1 || knows[0].ID != "e1" {
+		t.Errorf("Expected snapshot type index [e1], got %v", knows)
+	}
+	if people := snap.NodesByLabel("Person"); len(people) != 2 {
+		t.Errorf("Expected 2 people in snapshot, got %d", len(people))
+	}
+	nodes, _ := snap.FindNodesInRange("Person", "age", 0, nil)
+	if got, want := nodeIDs(nodes), []string{"A", "B"}; !equalIDs(got, want) {
+		t.Errorf("Expected snapshot index lookup %v, got %v", want, got)
+	}
+	if len(snap.Constraints()) != 0 {
+		t.Error("Expected snapshot not to see a later constraint")
+	}
+	if path, _, err := snap.ShortestPath("A", "B"); err != nil || len(path) != 2 {
+		t.Errorf("Expected snapshot path A->B, got %v (%v)", path, err)
+	}
+
+	// The live graph sees every write
+	nodes, _ = g.FindNodesInRange("Person", "age", 0, nil)
+	if got, want := nodeIDs(nodes), []string{"B", "C"}; !equalIDs(got, want) {
+		t.Errorf("Expected live index lookup %v, got %v", want, got)
+	}
+	if knows := g.EdgesByType("KNOWS"); len(knows) != 1 || knows[0].ID != "e2" {
+		t.Errorf("Expected live type index [e2], got %v", knows)
+	}
+}
+
+// TestSnapshotsAcrossEpochs tests that consecutive snapshots each keep their
+// own version
+func TestSnapshotsAcrossEpochs(t *testing.T) {
+	g := NewGraph()
+	var snaps []Reader
+	for i := 0; i < 3; i++ {
+		g.AddNode(&Node{ID: fmt.Sprint(i)})
+		snaps = append(snaps, g.Snapshot())
+	}
+	for i, snap := range snaps {
+		if got := len(snap.Nodes()); got != i+1 {
+			t.Errorf("Expected snapshot %d to hold %d nodes, got %d", i, i+1, got)
+		}
+	}
+}
+
+// TestSnapshotConcurrentWrites tests that reads on a snapshot run alongside
+// writes to the graph; run with -race
+func TestSnapshotConcurrentWrites(t *testing.T) {
+	g := NewGraph()
+	for i := 0; i < 100; i++ {
+		g.AddNode(&Node{ID: fmt.Sprint(i)})
+		if i > 0 {
+			g.AddEdge(&Edge{ID: fmt.Sprintf("e%d", i), From: fmt.Sprint(i - 1), To: fmt.Sprint(i), Weight: 1.0})
+		}
+	}
+	snap := g.Snapshot()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if _, dist, err := snap.ShortestPath("0", "99"); err != nil || dist != 99 {
+				t.Errorf("Expected distance 99 on snapshot, got %f (%v)", dist, err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 1; i < 100; i++ {
+			g.DeleteEdgeByID(fmt.Sprintf("e%d", i))
+		}
+	}()
+	wg.Wait()
+
+	if _, _, err := g.ShortestPath("0", "99"); err == nil {
+		t.Error("Expected live graph to have no path after deletes")
+	}
+}
+
+// TestAllNodesAndEdges tests the iterators and counts
+func TestAllNodesAndEdges(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B"})
+	g.AddEdge(&Edge{ID: "e2", From: "A", To: "B"})
+
+	if g.NodeCount() != 2 || g.EdgeCount() != 2 {
+		t.Errorf("Expected 2 nodes and 2 edges, got %d and %d", g.NodeCount(), g.EdgeCount())
+	}
+
+	seen := make(map[string]bool)
+	for id, node := range g.AllNodes() {
+		if id != node.ID {
+			t.Errorf("Expected key '%s' to match node ID '%s'", id, node.ID)
+		}
+		seen[id] = true
+	}
+	if !seen["A"] || !seen["B"] {
+		t.Errorf("Expected to visit A and B, got %v", seen)
+	}
+
+	count := 0
+	for id, edge := range g.AllEdges() {
+		if id != edge.ID {
+			t.Errorf("Expected key '%s' to match edge ID '%s'", id, edge.ID)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 edges, got %d", count)
+	}
+
+	// Stopping early is honoured
+	count = 0
+	for range g.AllNodes() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("Expected iteration to stop after 1 node, got %d", count)
+	}
+}
+
+// TestAllNodesWritesDuringIteration tests that the graph may be written while
+// iterating; run with -race
+func TestAllNodesWritesDuringIteration(t *testing.T) {
+	g := NewGraph()
+	for i := 0; i < 10; i++ {
+		g.AddNode(&Node{ID: fmt.Sprint(i)})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 10; i < 50; i++ {
+			g.AddNode(&Node{ID: fmt.Sprint(i)})
+		}
+	}()
+
+	copies := 0
+	for id := range g.AllNodes() {
+		g.AddNode(&Node{ID: "copy-" + id})
+		copies++
+	}
+	wg.Wait()
+
+	if copies < 10 {
+		t.Errorf("Expected at least the 10 initial nodes to be visited, got %d", copies)
+	}
+	if g.NodeCount() != 50+copies {
+		t.Errorf("Expected %d nodes, got %d", 50+copies, g.NodeCount())
+	}
+}
+
+// TestIterationLeavesEpoch tests that iterating does not make later writes copy the graph's maps
+func TestIterationLeavesEpoch(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B"})
+	epoch := g.(*graphImpl).s.epoch
+
+	for range g.AllNodes() {
+	}
+	for range g.AllEdges() {
+	}
+	if got := g.(*graphImpl).s.epoch; got != epoch {
+		t.Errorf("Expected iterating to leave the epoch at %d, got %d", epoch, got)
+	}
+
+	// Nodes added after iteration starts are not visited
+	count := 0
+	for id := range g.AllNodes() {
+		g.AddNode(&Node{ID: "copy-" + id})
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 nodes to be visited, got %d", count)
+	}
+}
@@ -0,0 +1,313 @@
+package graph
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+
+	pq "github.com/camwhite18/ArachneGraph/pkg/priority_queue"
+)
+
+// SpanningAlgorithm selects how MinimumSpanningTree picks its edges.
+type SpanningAlgorithm int
+
+const (
+	// Kruskal adds edges in order of weight, skipping those that would close
+	// a cycle, in O(E log E) time.
+	Kruskal SpanningAlgorithm = iota
+	// Prim grows a tree from one node at a time along the lightest edge
+	// leaving it, in O(E log V) time.
+	Prim
+)
+
+func (a SpanningAlgorithm) String() string {
+	switch a {
+	case Kruskal:
+		return "kruskal"
+	case Prim:
+		return "prim"
+	default:
+		return fmt.Sprintf("SpanningAlgorithm(%d)", int(a))
+	}
+}
+
+// SpanningTree holds the edges of a spanning tree or forest, sorted by ID, and
+// their total weight.
+type SpanningTree struct {
+	Edges  []*Edge
+	Weight float64
+}
+
+func newSpanningTree(edges []*Edge) *SpanningTree {
+	sort.Slice(edges, func(i, j int) bool { return edges[i].ID < edges[j].ID })
+	t := &SpanningTree{Edges: make([]*Edge, len(edges))}
+	for i, edge := range edges {
+		t.Edges[i] = edge.clone()
+		t.Weight += edge.Weight
+	}
+	return t
+}
+
+// MinimumSpanningTree returns a minimum spanning forest of the graph: for
+// each weakly connected component, a tree joining all its nodes whose edges
+// have the least total weight. Every edge is treated as undirected and
+// self-loops are never chosen. Both algorithms find a tree of the same
+// weight, but when edges share a weight they may choose different ones.
+// Negative weights are allowed.
+func (s *store) MinimumSpanningTree(alg SpanningAlgorithm) (*SpanningTree, error) {
+	return s.MinimumSpanningTreeContext(context.Background(), alg)
+}
+
+// MinimumSpanningTreeContext is MinimumSpanningTree that gives up with the
+// context's error once the context is done, or with ErrVisitLimit once it
+// visits more nodes than allowed.
+func (s *store) MinimumSpanningTreeContext(ctx context.Context, alg SpanningAlgorithm) (*SpanningTree, error) {
+	search := s.newSearch(ctx)
+	switch alg {
+	case Kruskal:
+		return s.kruskal(search)
+	case Prim:
+		return s.prim(search)
+	default:
+		return nil, fmt.Errorf("unknown spanning tree algorithm %v", alg)
+	}
+}
+
+func (s *store) kruskal(search *search) (*SpanningTree, error) {
+	forest := newUnionFind(0)
+	for id := range s.nodes.m {
+		if err := search.visit(); err != nil {
+			return nil, err
+		}
+		forest.add(id)
+	}
+
+	// Sorting ties by ID makes the choice between equal edges the same on
+	// every run
+	edges := sortedEdges(s.edges.m)
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].Weight < edges[j].Weight })
+
+	var chosen []*Edge
+	for _, edge := range edges {
+		if err := search.step(); err != nil {
+			return nil, err
+		}
+		if forest.union(edge.From, edge.To) {
+			chosen = append(chosen, edge)
+		}
+	}
+	return newSpanningTree(chosen), nil
+}
+
+func (s *store) prim(search *search) (*SpanningTree, error) {
+	inTree := make(map[string]bool, len(s.nodes.m))
+	// best holds the lightest edge joining each node outside the tree to it
+	best := make(map[string]*Edge)
+	var chosen []*Edge
+
+	for _, root := range sortedNodes(s.nodes.m) {
+		if inTree[root.ID] {
+			continue
+		}
+		var queue pq.PriorityQueue
+		heap.Push(&queue, &pq.PriorityQueueItem{NodeID: root.ID, Distance: 0})
+		for queue.Len() > 0 {
+			item := heap.Pop(&queue).(*pq.PriorityQueueItem)
+			current := item.NodeID
+			if inTree[current] || (best[current] != nil && item.Distance > best[current].Weight) {
+				continue
+			}
+			if err := search.visit(); err != nil {
+				return nil, err
+			}
+			inTree[current] = true
+			if edge := best[current]; edge != nil {
+				chosen = append(chosen, edge)
+			}
+
+			for _, edge := range s.incidentEdges(current) {
+				next := edge.Other(current)
+				if inTree[next] {
+					continue
+				}
+				if b := best[next]; b == nil || edge.Weight < b.Weight {
+					best[next] = edge
+					heap.Push(&queue, &pq.PriorityQueueItem{NodeID: next, Distance: edge.Weight})
+				}
+			}
+		}
+	}
+	return newSpanningTree(chosen), nil
+}
+
+// MinimumSpanningArborescence returns the directed tree of least total weight
+// whose edges lead from root to every other node, using the Chu-Liu/Edmonds
+// algorithm in O(VE) time. Edges are followed only in their direction, and
+// undirected edges either way. It fails if some node cannot be reached from
+// root. Negative weights are allowed.
+func (s *store) MinimumSpanningArborescence(root string) (*SpanningTree, error) {
+	return s.MinimumSpanningArborescenceContext(context.Background(), root)
+}
+
+// MinimumSpanningArborescenceContext is MinimumSpanningArborescence that gives
+// up with the context's error once the context is done, or with ErrVisitLimit
+// once it visits more nodes than allowed.
+func (s *store) MinimumSpanningArborescenceContext(ctx context.Context, root string) (*SpanningTree, error) {
+	if _, exists := s.nodes.m[root]; !exists {
+		return nil, fmt.Errorf("root node %q not found", root)
+	}
+	reached, err := s.BFSContext(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if len(reached) < len(s.nodes.m) {
+		seen := make(map[string]bool, len(reached))
+		for _, id := range reached {
+			seen[id] = true
+		}
+		for _, node := range sortedNodes(s.nodes.m) {
+			if !seen[node.ID] {
+				return nil, fmt.Errorf("node %q is not reachable from %q", node.ID, root)
+			}
+		}
+	}
+
+	nodes := sortedNodes(s.nodes.m)
+	index := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		index[node.ID] = i
+	}
+	edges := sortedEdges(s.edges.m)
+	var arcs []arc
+	for i, edge := range edges {
+		from, to := index[edge.From], index[edge.To]
+		arcs = append(arcs, arc{from: from, to: to, weight: edge.Weight, prev: i})
+		if !edge.Directed {
+			arcs = append(arcs, arc{from: to, to: from, weight: edge.Weight, prev: i})
+		}
+	}
+
+	chosen, err := edmonds(s.newSearch(ctx), len(nodes), index[root], arcs)
+	if err != nil {
+		return nil, err
+	}
+	tree := make([]*Edge, len(chosen))
+	for i, a := range chosen {
+		tree[i] = edges[arcs[a].prev]
+	}
+	return newSpanningTree(tree), nil
+}
+
+// arc is an edge of the graph Chu-Liu/Edmonds works on, between nodes
+// numbered from zero. prev is the index of the arc it stands for in the graph
+// one contraction up, or of the graph's edge at the top.
+type arc struct {
+	from, to int
+	weight   float64
+	prev     int
+}
+
+// edmonds returns the indexes of the arcs of a minimum arborescence of the
+// graph of n nodes, every one of which is reachable from root. Each node but
+// the root takes its lightest incoming arc; if those arcs form cycles, each
+// cycle is contracted into a single node and the smaller graph solved, its
+// arcs reweighted by what they would save against the cycle's own arc.
+func edmonds(search *search, n, root int, arcs []arc) ([]int, error) {
+	if err := search.step(); err != nil {
+		return nil, err
+	}
+
+	// Ties go to the first arc, which keeps the result the same on every run
+	in := make([]int, n)
+	for v := range in {
+		in[v] = -1
+	}
+	for i, a := range arcs {
+		if a.from != a.to && a.to != root && (in[a.to] == -1 || a.weight < arcs[in[a.to]].weight) {
+			in[a.to] = i
+		}
+	}
+
+	// Follow the chosen arcs backwards from each node; coming round to a node
+	// seen on the same walk closes a cycle
+	cycle := make([]int, n)
+	walk := make([]int, n)
+	for v := range cycle {
+		cycle[v], walk[v] = -1, -1
+	}
+	cycles := 0
+	for v := 0; v < n; v++ {
+		u := v
+		for u != root && walk[u] == -1 && cycle[u] == -1 {
+			walk[u] = v
+			u = arcs[in[u]].from
+		}
+		if u != root && walk[u] == v && cycle[u] == -1 {
+			for x := u; cycle[x] == -1; x = arcs[in[x]].from {
+				cycle[x] = cycles
+			}
+			cycles++
+		}
+	}
+
+	if cycles == 0 {
+		chosen := make([]int, 0, n-1)
+		for v, i := range in {
+			if v != root {
+				chosen = append(chosen, i)
+			}
+		}
+		return chosen, nil
+	}
+
+	// Number the cycles first and the other nodes after them
+	component := make([]int, n)
+	next := cycles
+	for v := range component {
+		if cycle[v] != -1 {
+			component[v] = cycle[v]
+		} else {
+			component[v] = next
+			next++
+		}
+	}
+	var contracted []arc
+	for i, a := range arcs {
+		from, to := component[a.from], component[a.to]
+		if from == to {
+			continue
+		}
+		weight := a.weight
+		if cycle[a.to] != -1 {
+			weight -= arcs[in[a.to]].weight
+		}
+		contracted = append(contracted, arc{from: from, to: to, weight: weight, prev: i})
+	}
+
+	sub, err := edmonds(search, next, component[root], contracted)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each cycle is entered by one arc of the smaller solution and keeps all
+	// its own arcs but the one into the node that arc enters
+	entry := make([]int, cycles)
+	for c := range entry {
+		entry[c] = -1
+	}
+	chosen := make([]int, 0, n-1)
+	for _, j := range sub {
+		i := contracted[j].prev
+		chosen = append(chosen, i)
+		if c := cycle[arcs[i].to]; c != -1 {
+			entry[c] = arcs[i].to
+		}
+	}
+	for v := 0; v < n; v++ {
+		if c := cycle[v]; c != -1 && entry[c] != v {
+			chosen = append(chosen, in[v])
+		}
+	}
+	return chosen, nil
+}
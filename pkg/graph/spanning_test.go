@@ -0,0 +1,226 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// newCablingGraph builds a network of sites joined by possible cable runs,
+// weighted by cost. The runs are directed, which spanning trees ignore:
+//
+//	A --7--> B --8--> C
+//	A --5--> D --9--> B --7--> E <--5-- C
+//	D --15--> E --8--> F <--6-- D
+//	E --9--> G <--11-- F
+//
+// X and Y form a separate island joined by two parallel runs.
+func newCablingGraph(t *testing.T) Graph {
+	t.Helper()
+	return buildGraph(t, nodesWithIDs("A", "B", "C", "D", "E", "F", "G", "X", "Y"), []*Edge{
+		{ID: "AB", From: "A", To: "B", Weight: 7.0},
+		{ID: "AD", From: "A", To: "D", Weight: 5.0},
+		{ID: "BC", From: "B", To: "C", Weight: 8.0},
+		{ID: "DB", From: "D", To: "B", Weight: 9.0},
+		{ID: "BE", From: "B", To: "E", Weight: 7.0},
+		{ID: "CE", From: "C", To: "E", Weight: 5.0},
+		{ID: "DE", From: "D", To: "E", Weight: 15.0},
+		{ID: "DF", From: "D", To: "F", Weight: 6.0},
+		{ID: "EF", From: "E", To: "F", Weight: 8.0},
+		{ID: "EG", From: "E", To: "G", Weight: 9.0},
+		{ID: "FG", From: "F", To: "G", Weight: 11.0},
+		{ID: "XY1", From: "X", To: "Y", Weight: 3.0},
+		{ID: "XY2", From: "Y", To: "X", Weight: 2.0},
+		{ID: "XX", From: "X", To: "X", Weight: -1.0},
+	})
+}
+
+// TestMinimumSpanningTree tests that both algorithms find the minimum spanning forest
+func TestMinimumSpanningTree(t *testing.T) {
+	g := newCablingGraph(t)
+
+	for _, alg := range []SpanningAlgorithm{Kruskal, Prim} {
+		tree, err := g.MinimumSpanningTree(alg)
+		if err != nil {
+			t.Fatalf("MinimumSpanningTree with %v failed: %v", alg, err)
+		}
+		want := []string{"AB", "AD", "BE", "CE", "DF", "EG", "XY2"}
+		if !reflect.DeepEqual(edgeIDs(tree.Edges), want) {
+			t.Errorf("Expected %v to choose %v, got %v", alg, want, edgeIDs(tree.Edges))
+		}
+		if tree.Weight != 41 {
+			t.Errorf("Expected %v to find weight 41, got %f", alg, tree.Weight)
+		}
+	}
+
+	if _, err := g.MinimumSpanningTree(SpanningAlgorithm(9)); err == nil {
+		t.Errorf("Expected error for an unknown algorithm")
+	}
+	tree, err := NewGraph().MinimumSpanningTree(Prim)
+	if err != nil || len(tree.Edges) != 0 || tree.Weight != 0 {
+		t.Errorf("Expected an empty tree for an empty graph, got %v: %v", tree, err)
+	}
+}
+
+// TestMinimumSpanningTreeRandom tests that Kruskal and Prim agree on random graphs
+func TestMinimumSpanningTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		g := NewGraph(WithDirectedness(UndirectedGraph))
+		for i := 0; i < 30; i++ {
+			if err := g.AddNode(&Node{ID: fmt.Sprintf("n%d", i)}); err != nil {
+				t.Fatalf("Failed to add node: %v", err)
+			}
+		}
+		for i := 0; i < 60; i++ {
+			edge := &Edge{ID: fmt.Sprintf("e%d", i), From: fmt.Sprintf("n%d", r.Intn(30)), To: fmt.Sprintf("n%d", r.Intn(30)), Weight: float64(r.Intn(20) - 5)}
+			if err := g.AddEdge(edge); err != nil {
+				t.Fatalf("Failed to add edge: %v", err)
+			}
+		}
+
+		kruskal, err := g.MinimumSpanningTree(Kruskal)
+		if err != nil {
+			t.Fatalf("MinimumSpanningTree with Kruskal failed: %v", err)
+		}
+		prim, err := g.MinimumSpanningTree(Prim)
+		if err != nil {
+			t.Fatalf("MinimumSpanningTree with Prim failed: %v", err)
+		}
+		if kruskal.Weight != prim.Weight || len(kruskal.Edges) != len(prim.Edges) {
+			t.Errorf("Expected Kruskal and Prim to agree, got %d edges of weight %f and %d of weight %f",
+				len(kruskal.Edges), kruskal.Weight, len(prim.Edges), prim.Weight)
+		}
+		if want := g.NodeCount() - g.ComponentCount(); len(kruskal.Edges) != want {
+			t.Errorf("Expected a forest of %d edges, got %d", want, len(kruskal.Edges))
+		}
+	}
+}
+
+// TestMinimumSpanningArborescence tests a case where the cheapest incoming edges form a cycle
+func TestMinimumSpanningArborescence(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"r", "a", "b", "c"} {
+		if err := g.AddNode(&Node{ID: id}); err != nil {
+			t.Fatalf("Failed to add node: %v", err)
+		}
+	}
+	edges := []*Edge{
+		{ID: "ra", From: "r", To: "a", Weight: 5.0},
+		{ID: "rb", From: "r", To: "b", Weight: 3.0},
+		{ID: "ab", From: "a", To: "b", Weight: 1.0},
+		{ID: "ba", From: "b", To: "a", Weight: 1.0},
+		{ID: "ac", From: "a", To: "c", Weight: 2.0},
+		{ID: "bc", From: "b", To: "c", Weight: 7.0},
+		{ID: "rc", From: "r", To: "c", Weight: 10.0},
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("Failed to add edge: %v", err)
+		}
+	}
+
+	tree, err := g.MinimumSpanningArborescence("r")
+	if err != nil {
+		t.Fatalf("MinimumSpanningArborescence failed: %v", err)
+	}
+	if want := []string{"ac", "ba", "rb"}; !reflect.DeepEqual(edgeIDs(tree.Edges), want) {
+		t.Errorf("Expected arborescence %v, got %v", want, edgeIDs(tree.Edges))
+	}
+	if tree.Weight != 6 {
+		t.Errorf("Expected weight 6, got %f", tree.Weight)
+	}
+
+	if _, err := g.MinimumSpanningArborescence("c"); err == nil {
+		t.Errorf("Expected error when nodes cannot be reached from the root")
+	}
+	if _, err := g.MinimumSpanningArborescence("missing"); err == nil {
+		t.Errorf("Expected error for a missing root")
+	}
+}
+
+// TestMinimumSpanningArborescenceRandom tests Chu-Liu/Edmonds against trying every choice of incoming edges
+func TestMinimumSpanningArborescenceRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		g := NewGraph()
+		ids := []string{"n0", "n1", "n2", "n3", "n4", "n5"}
+		for _, id := range ids {
+			if err := g.AddNode(&Node{ID: id}); err != nil {
+				t.Fatalf("Failed to add node: %v", err)
+			}
+		}
+		for i := 0; i < 14; i++ {
+			edge := &Edge{ID: fmt.Sprintf("e%02d", i), From: ids[r.Intn(len(ids))], To: ids[r.Intn(len(ids))], Weight: float64(r.Intn(10))}
+			if err := g.AddEdge(edge); err != nil {
+				t.Fatalf("Failed to add edge: %v", err)
+			}
+		}
+
+		want := bruteForceArborescence(g, "n0", ids)
+		tree, err := g.MinimumSpanningArborescence("n0")
+		if math.IsInf(want, 1) {
+			if err == nil {
+				t.Errorf("Expected error in round %d for nodes unreachable from the root", round)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("MinimumSpanningArborescence failed in round %d: %v", round, err)
+		}
+		if tree.Weight != want || len(tree.Edges) != len(ids)-1 {
+			t.Errorf("Expected weight %f in round %d, got %d edges of weight %f", want, round, len(tree.Edges), tree.Weight)
+		}
+		parent := make(map[string]string)
+		for _, edge := range tree.Edges {
+			if _, ok := parent[edge.To]; ok || edge.To == "n0" {
+				t.Errorf("Expected one edge into each node but the root in round %d, got %v", round, edgeIDs(tree.Edges))
+			}
+			parent[edge.To] = edge.From
+		}
+	}
+}
+
+// bruteForceArborescence tries every way of picking one incoming edge for each
+// node but the root, returning the least weight of those that form a tree, or
+// +Inf if none does.
+func bruteForceArborescence(g Graph, root string, ids []string) float64 {
+	var others []string
+	for _, id := range ids {
+		if id != root {
+			others = append(others, id)
+		}
+	}
+	best := math.Inf(1)
+	parent := make(map[string]*Edge)
+	var try func(i int)
+	try = func(i int) {
+		if i == len(others) {
+			weight := 0.0
+			for _, id := range others {
+				// Every node must lead back to the root without a cycle
+				current := id
+				for steps := 0; current != root; steps++ {
+					if steps > len(ids) {
+						return
+					}
+					current = parent[current].From
+				}
+				weight += parent[id].Weight
+			}
+			best = math.Min(best, weight)
+			return
+		}
+		incoming, _ := g.Incoming(others[i])
+		for _, edge := range incoming {
+			if edge.From != edge.To {
+				parent[others[i]] = edge
+				try(i + 1)
+			}
+		}
+	}
+	try(0)
+	return best
+}
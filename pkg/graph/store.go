@@ -0,0 +1,706 @@
+package graph
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+
+	pq "github.com/camwhite18/ArachneGraph/pkg/priority_queue"
+)
+
+// store holds the contents of a graph: a multigraph whose edges are
+// identified by their ID, so any number of parallel edges may connect the same
+// pair of nodes. The out and in adjacency maps are keyed by node ID and then
+// by edge ID; an undirected edge is in the out and in maps of both its
+// endpoints, and each node's adjacency also keeps its edges in the graph's
+// Ordering so that searches need not sort them. seq numbers the edges in
+// insertion order and negative counts the edges with a negative weight. The
+// labels and types maps index node IDs by label and edge IDs by type, indexes
+// holds the secondary property indexes and constraints the declared schema
+// rules, whose keys constraintOrder lists in the order of Constraints. It is
+// replaced rather than modified, so that frozen copies can share it.
+// connected, when the graph has a component index, holds its weakly connected
+// components.
+//
+// store does no locking of its own. Its exported methods implement Reader and
+// its unexported mutations back Writer; graphImpl and txImpl serialize access
+// to them.
+//
+// All containers are copy-on-write (see cowMap), and nodes and edges are never
+// modified once stored. Freezing a store for a snapshot is therefore a shallow
+// struct copy followed by an epoch bump on the live store, after which the
+// live store copies each container the first time it writes to it.
+type store struct {
+	nodes           cowMap[string, *Node]
+	edges           cowMap[string, *Edge]
+	out             cowMap[string, adjacency]
+	in              cowMap[string, adjacency]
+	labels          cowMap[string, cowMap[string, *Node]]
+	types           cowMap[string, cowMap[string, *Edge]]
+	indexes         cowMap[indexKey, *propertyIndex]
+	constraints     cowMap[Constraint, *constraintState]
+	constraintOrder []Constraint
+	connected       *unionFind
+	cfg             Config
+	seq             uint64
+	negative        int
+	epoch           uint64
+}
+
+func newStore(cfg Config) *store {
+	const epoch = 1
+	s := &store{
+		cfg:         cfg,
+		nodes:       newCowMap[string, *Node](epoch),
+		edges:       newCowMap[string, *Edge](epoch),
+		out:         newCowMap[string, adjacency](epoch),
+		in:          newCowMap[string, adjacency](epoch),
+		labels:      newCowMap[string, cowMap[string, *Node]](epoch),
+		types:       newCowMap[string, cowMap[string, *Edge]](epoch),
+		indexes:     newCowMap[indexKey, *propertyIndex](epoch),
+		constraints: newCowMap[Constraint, *constraintState](epoch),
+		epoch:       epoch,
+	}
+	if cfg.ComponentIndex {
+		s.connected = newUnionFind(epoch)
+	}
+	return s
+}
+
+// freeze returns a read-only copy of the store as it is now. The live store
+// moves to a new epoch so that its later writes copy the containers they
+// touch instead of modifying those shared with the frozen copy.
+func (s *store) freeze() *store {
+	frozen := *s
+	s.epoch++
+	return &frozen
+}
+
+// Nodes returns a copy of the node map, keyed by node ID.
+//
+// Deprecated: Use AllNodes, which builds no map and does not hold the graph's
+// lock while the loop body runs.
+func (s *store) Nodes() map[string]*Node {
+	nodes := make(map[string]*Node, len(s.nodes.m))
+	for id, node := range s.nodes.m {
+		nodes[id] = node.clone()
+	}
+	return nodes
+}
+
+// OutEdges returns a copy of the outgoing adjacency of the graph, keyed by
+// source node ID and then by target node ID. Of several parallel edges only
+// the one with the lowest ID is included.
+//
+// Deprecated: Use OutEdgesByID, which keeps parallel edges, or AllEdges,
+// which builds no maps and does not hold the graph's lock while the loop body
+// runs.
+func (s *store) OutEdges() map[string]map[string]*Edge {
+	out := make(map[string]map[string]*Edge, len(s.out.m))
+	for id, edges := range s.out.m {
+		out[id] = make(map[string]*Edge, len(edges.m))
+		for _, edge := range edges.m {
+			to := edge.Other(id)
+			if kept, exists := out[id][to]; !exists || edge.ID < kept.ID {
+				out[id][to] = edge
+			}
+		}
+		for to, edge := range out[id] {
+			out[id][to] = edge.clone()
+		}
+	}
+	return out
+}
+
+// OutEdgesByID returns a copy of the outgoing adjacency of the graph, keyed
+// by source node ID and then by edge ID.
+func (s *store) OutEdgesByID() map[string]map[string]*Edge {
+	out := make(map[string]map[string]*Edge, len(s.out.m))
+	for id, edges := range s.out.m {
+		out[id] = make(map[string]*Edge, len(edges.m))
+		for edgeID, edge := range edges.m {
+			out[id][edgeID] = edge.clone()
+		}
+	}
+	return out
+}
+
+// AllNodes iterates over copies of the nodes of the graph, keyed by ID, in no
+// particular order.
+func (s *store) AllNodes() iter.Seq2[string, *Node] {
+	return func(yield func(string, *Node) bool) {
+		for id, node := range s.nodes.m {
+			if !yield(id, node.clone()) {
+				return
+			}
+		}
+	}
+}
+
+// AllEdges iterates over copies of the edges of the graph, keyed by ID, in no
+// particular order.
+func (s *store) AllEdges() iter.Seq2[string, *Edge] {
+	return func(yield func(string, *Edge) bool) {
+		for id, edge := range s.edges.m {
+			if !yield(id, edge.clone()) {
+				return
+			}
+		}
+	}
+}
+
+// nodeList returns the stored nodes in no particular order. They are never
+// modified once stored, so the list stays valid after the store changes.
+func (s *store) nodeList() []*Node {
+	nodes := make([]*Node, 0, len(s.nodes.m))
+	for _, node := range s.nodes.m {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// edgeList returns the stored edges like nodeList.
+func (s *store) edgeList() []*Edge {
+	edges := make([]*Edge, 0, len(s.edges.m))
+	for _, edge := range s.edges.m {
+		edges = append(edges, edge)
+	}
+	return edges
+}
+
+func (s *store) Config() Config {
+	return s.cfg
+}
+
+func (s *store) NodeCount() int {
+	return len(s.nodes.m)
+}
+
+func (s *store) EdgeCount() int {
+	return len(s.edges.m)
+}
+
+// addNode stores a copy of the node, so that the caller cannot change the
+// stored node behind the graph's back.
+func (s *store) addNode(node *Node) error {
+	if node.ID == "" && s.cfg.IDGenerator != nil {
+		node.ID = s.cfg.IDGenerator()
+	}
+	node = node.clone()
+	if _, exists := s.nodes.m[node.ID]; exists {
+		return fmt.Errorf("node %q already exists", node.ID)
+	}
+	if err := s.checkNode(node); err != nil {
+		return err
+	}
+	s.nodes.mut(s.epoch)[node.ID] = node
+	s.indexNode(node)
+	if s.connected != nil {
+		s.mutConnected().add(node.ID)
+	}
+	return nil
+}
+
+func (s *store) GetNode(id string) (*Node, error) {
+	node, exists := s.nodes.m[id]
+	if !exists {
+		return nil, fmt.Errorf("node %q not found", id)
+	}
+	return node.clone(), nil
+}
+
+func (s *store) deleteNode(id string) error {
+	if _, exists := s.nodes.m[id]; !exists {
+		return fmt.Errorf("node %q not found", id)
+	}
+	edges := s.incidentEdges(id)
+	for _, edge := range edges {
+		s.removeEdge(edge)
+	}
+	s.unindexNode(s.nodes.m[id])
+	delete(s.nodes.mut(s.epoch), id)
+	s.disconnectNode(id, edges)
+	return nil
+}
+
+// addEdge stores a copy of the edge, so that the caller cannot change the
+// stored edge behind the graph's back. The copy's Directed field is set from
+// the graph's Directedness.
+func (s *store) addEdge(edge *Edge) error {
+	if edge.ID == "" && s.cfg.IDGenerator != nil {
+		edge.ID = s.cfg.IDGenerator()
+	}
+	edge = edge.clone()
+	switch s.cfg.Directedness {
+	case DirectedGraph:
+		edge.Directed = true
+	case UndirectedGraph:
+		edge.Directed = false
+	}
+	s.seq++
+	edge.seq = s.seq
+	return s.insertEdge(edge)
+}
+
+// insertEdge validates and links an edge that already has its sequence number
+// and direction. Rolled back deletions reinsert edges through it to keep their
+// place in the insertion order. The edge is stored as it is, so it must not be
+// shared with the caller.
+func (s *store) insertEdge(edge *Edge) error {
+	if edge.ID == "" {
+		return fmt.Errorf("edge from %q to %q has no ID", edge.From, edge.To)
+	}
+	if _, exists := s.edges.m[edge.ID]; exists {
+		return fmt.Errorf("edge %q already exists", edge.ID)
+	}
+	if _, exists := s.nodes.m[edge.From]; !exists {
+		return fmt.Errorf("node %q not found", edge.From)
+	}
+	if _, exists := s.nodes.m[edge.To]; !exists {
+		return fmt.Errorf("node %q not found", edge.To)
+	}
+	if edge.From == edge.To && !s.cfg.SelfLoops {
+		return fmt.Errorf("edge %q is a self-loop on %q, which the graph does not allow", edge.ID, edge.From)
+	}
+	if !s.cfg.Multigraph {
+		for _, other := range s.out.m[edge.From].m {
+			if other.Other(edge.From) == edge.To {
+				return fmt.Errorf("edge %q would be parallel to edge %q, which the graph does not allow", edge.ID, other.ID)
+			}
+		}
+	}
+	if err := s.checkEdge(edge); err != nil {
+		return err
+	}
+	s.linkEdge(edge)
+	return nil
+}
+
+// linkEdge adds an edge to the edge map, adjacency and indexes. An undirected
+// edge is both outgoing and incoming at each of its endpoints.
+func (s *store) linkEdge(edge *Edge) {
+	s.edges.mut(s.epoch)[edge.ID] = edge
+	s.link(&s.out, edge.From, edge)
+	s.link(&s.in, edge.To, edge)
+	if !edge.Directed {
+		s.link(&s.out, edge.To, edge)
+		s.link(&s.in, edge.From, edge)
+	}
+	if edge.Weight < 0 {
+		s.negative++
+	}
+	s.indexEdge(edge)
+	if s.connected != nil {
+		s.mutConnected().union(edge.From, edge.To)
+	}
+}
+
+// GetEdge returns an edge from one node to another. Undirected edges are found
+// from either endpoint. When several parallel
+// edges connect the pair, the one with the lowest ID is returned; use
+// EdgesBetween or GetEdgeByID to address a specific one.
+func (s *store) GetEdge(from, to string) (*Edge, error) {
+	edges := s.edgesBetween(from, to)
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("edge from %q to %q not found", from, to)
+	}
+	return edges[0].clone(), nil
+}
+
+func (s *store) GetEdgeByID(id string) (*Edge, error) {
+	edge, exists := s.edges.m[id]
+	if !exists {
+		return nil, fmt.Errorf("edge %q not found", id)
+	}
+	return edge.clone(), nil
+}
+
+// EdgesBetween returns every edge from one node to another, sorted by ID.
+func (s *store) EdgesBetween(from, to string) ([]*Edge, error) {
+	if _, exists := s.nodes.m[from]; !exists {
+		return nil, fmt.Errorf("node %q not found", from)
+	}
+	if _, exists := s.nodes.m[to]; !exists {
+		return nil, fmt.Errorf("node %q not found", to)
+	}
+	return cloneEdges(s.edgesBetween(from, to)), nil
+}
+
+// deleteEdge removes every edge from one node to another.
+func (s *store) deleteEdge(from, to string) error {
+	edges := s.edgesBetween(from, to)
+	if len(edges) == 0 {
+		return fmt.Errorf("edge from %q to %q not found", from, to)
+	}
+	for _, edge := range edges {
+		s.removeEdge(edge)
+	}
+	s.disconnect(edges)
+	return nil
+}
+
+func (s *store) deleteEdgeByID(id string) error {
+	edge, exists := s.edges.m[id]
+	if !exists {
+		return fmt.Errorf("edge %q not found", id)
+	}
+	s.removeEdge(edge)
+	s.disconnect([]*Edge{edge})
+	return nil
+}
+
+// edgesBetween returns the edges from one node to another sorted by ID,
+// including undirected edges stored the other way around.
+func (s *store) edgesBetween(from, to string) []*Edge {
+	var edges []*Edge
+	for _, edge := range s.out.m[from].m {
+		if edge.Other(from) == to {
+			edges = append(edges, edge)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].ID < edges[j].ID })
+	return edges
+}
+
+// removeEdge unlinks an edge from the graph.
+func (s *store) removeEdge(edge *Edge) {
+	delete(s.edges.mut(s.epoch), edge.ID)
+	s.unlink(&s.out, edge.From, edge)
+	s.unlink(&s.in, edge.To, edge)
+	if !edge.Directed {
+		s.unlink(&s.out, edge.To, edge)
+		s.unlink(&s.in, edge.From, edge)
+	}
+	if edge.Weight < 0 {
+		s.negative--
+	}
+	s.unindexEdge(edge)
+}
+
+// incidentEdges returns every edge entering or leaving a node, sorted by ID.
+func (s *store) incidentEdges(id string) []*Edge {
+	seen := make(map[string]*Edge, len(s.out.m[id].m)+len(s.in.m[id].m))
+	for edgeID, edge := range s.out.m[id].m {
+		seen[edgeID] = edge
+	}
+	for edgeID, edge := range s.in.m[id].m {
+		seen[edgeID] = edge
+	}
+	return sortedEdges(seen)
+}
+
+// adjacency holds one node's out or in edges, keyed by edge ID. Unless the
+// graph is Unordered, ordered holds the same edges in the graph's Ordering;
+// it belongs to the map's epoch and is copied along with it.
+type adjacency struct {
+	cowMap[string, *Edge]
+	ordered []*Edge
+}
+
+// link adds an edge to the adjacency of node id, keeping it in order.
+func (s *store) link(c *cowMap[string, adjacency], id string, edge *Edge) {
+	outer := c.mut(s.epoch)
+	adj := outer[id]
+	// An undirected self-loop is linked at the same node twice
+	if _, exists := adj.m[edge.ID]; exists {
+		return
+	}
+	if adj.epoch != s.epoch {
+		adj.ordered = slices.Clone(adj.ordered)
+	}
+	adj.mut(s.epoch)[edge.ID] = edge
+	if s.cfg.Ordering != Unordered {
+		i, _ := slices.BinarySearchFunc(adj.ordered, edge, s.edgeOrder(id))
+		adj.ordered = slices.Insert(adj.ordered, i, edge)
+	}
+	outer[id] = adj
+}
+
+// unlink removes an edge from the adjacency of node id, dropping the
+// adjacency once it is empty.
+func (s *store) unlink(c *cowMap[string, adjacency], id string, edge *Edge) {
+	adj, exists := c.m[id]
+	if !exists {
+		return
+	}
+	if _, exists := adj.m[edge.ID]; !exists {
+		return
+	}
+	outer := c.mut(s.epoch)
+	if adj.epoch != s.epoch {
+		adj.ordered = slices.Clone(adj.ordered)
+	}
+	m := adj.mut(s.epoch)
+	delete(m, edge.ID)
+	if len(m) == 0 {
+		delete(outer, id)
+		return
+	}
+	if i, found := slices.BinarySearchFunc(adj.ordered, edge, s.edgeOrder(id)); found {
+		adj.ordered = slices.Delete(adj.ordered, i, i+1)
+	}
+	outer[id] = adj
+}
+
+// edgeOrder returns the comparison that puts the edges at node id in the
+// order set by the graph's Ordering.
+func (s *store) edgeOrder(id string) func(a, b *Edge) int {
+	switch s.cfg.Ordering {
+	case OrderByInsertion:
+		return func(a, b *Edge) int { return cmp.Compare(a.seq, b.seq) }
+	case OrderByComparator:
+		return func(a, b *Edge) int {
+			return cmp.Or(s.cfg.EdgeComparator(a, b), cmp.Compare(a.ID, b.ID))
+		}
+	default:
+		return func(a, b *Edge) int {
+			return cmp.Or(cmp.Compare(a.Other(id), b.Other(id)), cmp.Compare(a.ID, b.ID))
+		}
+	}
+}
+
+// adjacent returns the edges of one of a node's adjacencies in the order set
+// by the graph's Ordering. The slice is shared with the store and must not be
+// modified.
+func (s *store) adjacent(adj adjacency) []*Edge {
+	if s.cfg.Ordering != Unordered {
+		return adj.ordered
+	}
+	edges := make([]*Edge, 0, len(adj.m))
+	for _, edge := range adj.m {
+		edges = append(edges, edge)
+	}
+	return edges
+}
+
+// Neighbors returns the outgoing edges of a node, including the undirected
+// edges it is an endpoint of. When types are given, only edges of one of
+// those types are returned. Use Edge.Other to find the neighbor an edge leads
+// to.
+func (s *store) Neighbors(id string, types ...string) ([]*Edge, error) {
+	if _, exists := s.nodes.m[id]; !exists {
+		return nil, fmt.Errorf("node %q not found", id)
+	}
+	var edges []*Edge
+	for _, edge := range s.adjacent(s.out.m[id]) {
+		if edge.hasType(types) {
+			edges = append(edges, edge.clone())
+		}
+	}
+	return edges, nil
+}
+
+// Incoming returns the incoming edges of a node, including the undirected
+// edges it is an endpoint of. When types are given, only edges of one of
+// those types are returned.
+func (s *store) Incoming(id string, types ...string) ([]*Edge, error) {
+	if _, exists := s.nodes.m[id]; !exists {
+		return nil, fmt.Errorf("node %q not found", id)
+	}
+	var edges []*Edge
+	for _, edge := range s.adjacent(s.in.m[id]) {
+		if edge.hasType(types) {
+			edges = append(edges, edge.clone())
+		}
+	}
+	return edges, nil
+}
+
+// NodesByLabel returns the nodes carrying a label, sorted by ID.
+func (s *store) NodesByLabel(label string) []*Node {
+	nodes := make([]*Node, 0, len(s.labels.m[label].m))
+	for _, node := range s.labels.m[label].m {
+		nodes = append(nodes, node.clone())
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// EdgesByType returns the edges of a type, sorted by ID. Untyped edges are
+// indexed under the empty type.
+func (s *store) EdgesByType(edgeType string) []*Edge {
+	edges := make([]*Edge, 0, len(s.types.m[edgeType].m))
+	for _, edge := range s.types.m[edgeType].m {
+		edges = append(edges, edge.clone())
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].ID < edges[j].ID })
+	return edges
+}
+
+// DFS performs depth-first search starting from the given node
+func (s *store) DFS(start string) ([]string, error) {
+	return s.DFSContext(context.Background(), start)
+}
+
+// DFSContext is DFS that gives up with the context's error once the context
+// is done, or with ErrVisitLimit once it visits more nodes than allowed.
+func (s *store) DFSContext(ctx context.Context, start string) ([]string, error) {
+	if _, exists := s.nodes.m[start]; !exists {
+		return nil, fmt.Errorf("node %q not found", start)
+	}
+
+	search := s.newSearch(ctx)
+	visited := make(map[string]bool)
+	var result []string
+	stack := []string{start}
+
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[id] {
+			continue
+		}
+		if err := search.visit(); err != nil {
+			return nil, err
+		}
+		visited[id] = true
+		result = append(result, id)
+
+		// Visit neighbors in the graph's order, pushing them in reverse so
+		// that the first is popped first
+		edges := s.adjacent(s.out.m[id])
+		for i := len(edges) - 1; i >= 0; i-- {
+			if next := edges[i].Other(id); !visited[next] {
+				stack = append(stack, next)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// BFS performs breadth-first search starting from the given node
+func (s *store) BFS(start string) ([]string, error) {
+	return s.BFSContext(context.Background(), start)
+}
+
+// BFSContext is BFS that gives up with the context's error once the context
+// is done, or with ErrVisitLimit once it visits more nodes than allowed.
+func (s *store) BFSContext(ctx context.Context, start string) ([]string, error) {
+	if _, exists := s.nodes.m[start]; !exists {
+		return nil, fmt.Errorf("node %q not found", start)
+	}
+
+	search := s.newSearch(ctx)
+	visited := make(map[string]bool)
+	var result []string
+	queue := []string{start}
+	visited[start] = true
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if err := search.visit(); err != nil {
+			return nil, err
+		}
+		result = append(result, current)
+
+		for _, edge := range s.adjacent(s.out.m[current]) {
+			if next := edge.Other(current); !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ShortestPath computes the shortest path using Dijkstra's algorithm. It
+// fails with ErrNegativeWeight if any edge has a negative weight.
+func (s *store) ShortestPath(source, target string) ([]string, float64, error) {
+	return s.ShortestPathContext(context.Background(), source, target)
+}
+
+// ShortestPathContext is ShortestPath that gives up with the context's error
+// once the context is done, or with ErrVisitLimit once it settles more nodes
+// than allowed.
+func (s *store) ShortestPathContext(ctx context.Context, source, target string) ([]string, float64, error) {
+	return s.shortestPath(ctx, source, target, nil)
+}
+
+// shortestPath runs A* from source to target, which with a nil heuristic is
+// Dijkstra's algorithm.
+func (s *store) shortestPath(ctx context.Context, source, target string, heuristic func(a, b *Node) float64) ([]string, float64, error) {
+	if _, exists := s.nodes.m[source]; !exists {
+		return nil, 0, fmt.Errorf("source node %q not found", source)
+	}
+	if _, exists := s.nodes.m[target]; !exists {
+		return nil, 0, fmt.Errorf("target node %q not found", target)
+	}
+	if s.negative > 0 {
+		return nil, 0, ErrNegativeWeight
+	}
+
+	// estimate is the heuristic's remaining distance from a node to the
+	// target, computed once per node
+	estimates := make(map[string]float64)
+	estimate := func(id string) float64 {
+		if heuristic == nil {
+			return 0
+		}
+		h, ok := estimates[id]
+		if !ok {
+			h = heuristic(s.nodes.m[id], s.nodes.m[target])
+			estimates[id] = h
+		}
+		return h
+	}
+
+	// Initialise distances and previous nodes. Nodes not yet reached have
+	// no distance, which stands for infinity.
+	dist := map[string]float64{source: 0}
+	prev := make(map[string]string)
+
+	// Priority queue ordered by distance plus estimate
+	priorityQueue := make(pq.PriorityQueue, 0)
+	heap.Init(&priorityQueue)
+	heap.Push(&priorityQueue, &pq.PriorityQueueItem{NodeID: source, Distance: estimate(source)})
+
+	search := s.newSearch(ctx)
+
+	for priorityQueue.Len() > 0 {
+		item := heap.Pop(&priorityQueue).(*pq.PriorityQueueItem)
+		current := item.NodeID
+
+		// Skip entries superseded by a shorter path found since they were
+		// queued. A heuristic that is admissible but not consistent can
+		// reopen a node this way.
+		if item.Distance > dist[current]+estimate(current) {
+			continue
+		}
+		if err := search.visit(); err != nil {
+			return nil, 0, err
+		}
+
+		// If we reached the target, reconstruct path
+		if current == target {
+			var path []string
+			for node := target; node != ""; node = prev[node] {
+				path = append([]string{node}, path...)
+				if node == source {
+					break
+				}
+			}
+			return path, dist[target], nil
+		}
+
+		// Check neighbours in the graph's order, so that ties between
+		// equally short paths are always broken the same way
+		for _, edge := range s.adjacent(s.out.m[current]) {
+			neighbor := edge.Other(current)
+			alt := dist[current] + edge.Weight
+			if d, reached := dist[neighbor]; !reached || alt < d {
+				dist[neighbor] = alt
+				prev[neighbor] = current
+				heap.Push(&priorityQueue, &pq.PriorityQueueItem{NodeID: neighbor, Distance: alt + estimate(neighbor)})
+			}
+		}
+	}
+
+	return nil, 0, fmt.Errorf("no path found from %q to %q", source, target)
+}
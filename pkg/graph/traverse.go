@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+)
+
+// Direction selects which edges a traversal follows out of a node.
+type Direction int
+
+const (
+	// OutDirection follows edges leaving the node.
+	OutDirection Direction = iota
+	// InDirection follows edges entering the node, against their direction.
+	InDirection
+	// BothDirections follows edges regardless of their direction.
+	BothDirections
+)
+
+func (d Direction) String() string {
+	switch d {
+	case OutDirection:
+		return "out"
+	case InDirection:
+		return "in"
+	case BothDirections:
+		return "both"
+	default:
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+}
+
+// TraversalStrategy selects the order in which a traversal visits nodes.
+type TraversalStrategy int
+
+const (
+	BreadthFirst TraversalStrategy = iota
+	DepthFirst
+)
+
+// VisitAction tells a traversal how to go on after a visit.
+type VisitAction int
+
+const (
+	// Continue expands the visited node as usual.
+	Continue VisitAction = iota
+	// Prune keeps the visited node but does not follow its edges.
+	Prune
+	// Stop ends the traversal after the visited node.
+	Stop
+)
+
+// Visit is a node reached by a traversal. Depth is the number of edges
+// followed from the start node and Parent is the edge the node was reached
+// through, nil for the start node.
+type Visit struct {
+	Node   *Node
+	Depth  int
+	Parent *Edge
+}
+
+// TraversalOptions configures Traverse. The zero value visits every node
+// reachable along outgoing edges, breadth first.
+type TraversalOptions struct {
+	Strategy  TraversalStrategy
+	Direction Direction
+	// MaxDepth limits how many edges away from the start node the traversal
+	// goes. Zero means no limit.
+	MaxDepth int
+	// EdgeFilter, when set, restricts the traversal to the edges it accepts.
+	EdgeFilter func(edge *Edge) bool
+	// NodeFilter, when set, restricts the traversal to the nodes it accepts.
+	// Rejected nodes are neither visited nor traversed through. The start
+	// node is always visited.
+	NodeFilter func(node *Node) bool
+	// Visitor, when set, is called for every visit in order and decides how
+	// the traversal goes on.
+	Visitor func(v Visit) VisitAction
+}
+
+// pendingVisit is a node waiting to be visited by Traverse.
+type pendingVisit struct {
+	id     string
+	depth  int
+	parent *Edge
+}
+
+// Traverse walks the graph from start as configured by opts and returns the
+// visits made, in order. Each node is visited once, through the first edge
+// that reaches it; breadth first that is along a path with the fewest edges.
+// Edges are followed in the graph's Ordering. The nodes and edges passed to
+// filters are shared with the graph and must not be modified, and the
+// callbacks must not use the graph, which is locked while they run.
+func (s *store) Traverse(start string, opts TraversalOptions) ([]Visit, error) {
+	return s.TraverseContext(context.Background(), start, opts)
+}
+
+// TraverseContext is Traverse that gives up with the context's error once the
+// context is done, or with ErrVisitLimit once it visits more nodes than
+// allowed.
+func (s *store) TraverseContext(ctx context.Context, start string, opts TraversalOptions) ([]Visit, error) {
+	if _, exists := s.nodes.m[start]; !exists {
+		return nil, fmt.Errorf("node %q not found", start)
+	}
+	if opts.Direction < OutDirection || opts.Direction > BothDirections {
+		return nil, fmt.Errorf("unknown direction %v", opts.Direction)
+	}
+
+	search := s.newSearch(ctx)
+	visited := make(map[string]bool)
+	var visits []Visit
+	pending := []pendingVisit{{id: start}}
+
+	for len(pending) > 0 {
+		var p pendingVisit
+		if opts.Strategy == DepthFirst {
+			p = pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+		} else {
+			p = pending[0]
+			pending = pending[1:]
+		}
+		if visited[p.id] {
+			continue
+		}
+		if err := search.visit(); err != nil {
+			return nil, err
+		}
+		visited[p.id] = true
+
+		visit := Visit{Node: s.nodes.m[p.id].clone(), Depth: p.depth}
+		if p.parent != nil {
+			visit.Parent = p.parent.clone()
+		}
+		visits = append(visits, visit)
+
+		action := Continue
+		if opts.Visitor != nil {
+			action = opts.Visitor(visit)
+		}
+		if action == Stop {
+			break
+		}
+		if action == Prune || (opts.MaxDepth > 0 && p.depth >= opts.MaxDepth) {
+			continue
+		}
+
+		var next []pendingVisit
+		for _, edge := range s.traversable(p.id, opts.Direction) {
+			if opts.EdgeFilter != nil && !opts.EdgeFilter(edge) {
+				continue
+			}
+			neighbor := edge.Other(p.id)
+			if visited[neighbor] {
+				continue
+			}
+			if opts.NodeFilter != nil && !opts.NodeFilter(s.nodes.m[neighbor]) {
+				continue
+			}
+			next = append(next, pendingVisit{id: neighbor, depth: p.depth + 1, parent: edge})
+		}
+		if opts.Strategy == DepthFirst {
+			// Push in reverse so that the first edge is followed first
+			for i := len(next) - 1; i >= 0; i-- {
+				pending = append(pending, next[i])
+			}
+		} else {
+			pending = append(pending, next...)
+		}
+	}
+	return visits, nil
+}
+
+// traversable returns the edges a traversal in the given direction may follow
+// out of a node, in the graph's order.
+func (s *store) traversable(id string, dir Direction) []*Edge {
+	switch dir {
+	case InDirection:
+		return s.adjacent(s.in.m[id])
+	case BothDirections:
+		out, in := s.out.m[id], s.in.m[id]
+		if s.cfg.Ordering == Unordered {
+			edges := s.adjacent(out)
+			for edgeID, edge := range in.m {
+				if _, exists := out.m[edgeID]; !exists {
+					edges = append(edges, edge)
+				}
+			}
+			return edges
+		}
+		// Merge the two ordered lists, taking an undirected edge, which is in
+		// both, once
+		order := s.edgeOrder(id)
+		edges := make([]*Edge, 0, len(out.ordered)+len(in.ordered))
+		i, j := 0, 0
+		for i < len(out.ordered) && j < len(in.ordered) {
+			switch c := order(out.ordered[i], in.ordered[j]); {
+			case c < 0:
+				edges = append(edges, out.ordered[i])
+				i++
+			case c > 0:
+				edges = append(edges, in.ordered[j])
+				j++
+			default:
+				edges = append(edges, out.ordered[i])
+				i++
+				j++
+			}
+		}
+		edges = append(edges, out.ordered[i:]...)
+		return append(edges, in.ordered[j:]...)
+	default:
+		return s.adjacent(s.out.m[id])
+	}
+}
@@ -0,0 +1,131 @@
+package graph
+
+import "testing"
+
+// newSocialGraph builds a chain of friendships with a follower on the side:
+//
+//	me -knows-> ann -knows-> bob -knows-> cat -knows-> dan
+//	eve -follows-> me
+//
+// bob is inactive.
+func newSocialGraph(t *testing.T) Graph {
+	t.Helper()
+	nodes := nodesWithIDs("me", "ann", "bob", "cat", "dan", "eve")
+	for _, node := range nodes {
+		node.Properties = map[string]any{"active": node.ID != "bob"}
+	}
+	return buildGraph(t, nodes, []*Edge{
+		{ID: "k1", From: "me", To: "ann", Type: "knows"},
+		{ID: "k2", From: "ann", To: "bob", Type: "knows"},
+		{ID: "k3", From: "bob", To: "cat", Type: "knows"},
+		{ID: "k4", From: "cat", To: "dan", Type: "knows"},
+		{ID: "f1", From: "eve", To: "me", Type: "follows"},
+	})
+}
+
+func visitIDs(visits []Visit) []string {
+	ids := make([]string, len(visits))
+	for i, v := range visits {
+		ids[i] = v.Node.ID
+	}
+	return ids
+}
+
+// TestTraverseDepthAndParents tests that visits carry their depth and parent edge
+func TestTraverseDepthAndParents(t *testing.T) {
+	g := newSocialGraph(t)
+
+	visits, err := g.Traverse("me", TraversalOptions{MaxDepth: 3})
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if got := visitIDs(visits); !equalIDs(got, []string{"me", "ann", "bob", "cat"}) {
+		t.Fatalf("Expected visits [me ann bob cat], got %v", got)
+	}
+	for i, v := range visits {
+		if v.Depth != i {
+			t.Errorf("Expected %s at depth %d, got %d", v.Node.ID, i, v.Depth)
+		}
+		if i == 0 && v.Parent != nil {
+			t.Errorf("Expected the start node to have no parent")
+		}
+		if i > 0 && (v.Parent == nil || v.Parent.To != v.Node.ID) {
+			t.Errorf("Expected %s to be reached through its incoming edge, got %v", v.Node.ID, v.Parent)
+		}
+	}
+}
+
+// TestTraverseFilters tests edge and node predicates and directions
+func TestTraverseFilters(t *testing.T) {
+	g := newSocialGraph(t)
+
+	// Active friends within 3 hops: bob is skipped, and so is everyone past him
+	active := func(n *Node) bool { return n.Properties["active"] == true }
+	visits, err := g.Traverse("me", TraversalOptions{MaxDepth: 3, NodeFilter: active})
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if got := visitIDs(visits); !equalIDs(got, []string{"me", "ann"}) {
+		t.Errorf("Expected visits [me ann], got %v", got)
+	}
+
+	knows := func(e *Edge) bool { return e.Type == "knows" }
+	visits, _ = g.Traverse("me", TraversalOptions{Direction: BothDirections, EdgeFilter: knows})
+	if got := visitIDs(visits); len(got) != 5 {
+		t.Errorf("Expected 5 nodes over knows edges, got %v", got)
+	}
+
+	visits, _ = g.Traverse("me", TraversalOptions{Direction: InDirection})
+	if got := visitIDs(visits); !equalIDs(got, []string{"me", "eve"}) {
+		t.Errorf("Expected incoming visits [me eve], got %v", got)
+	}
+
+	if _, err := g.Traverse("me", TraversalOptions{Direction: Direction(9)}); err == nil {
+		t.Errorf("Expected error for an unknown direction")
+	}
+	if _, err := g.Traverse("nobody", TraversalOptions{}); err == nil {
+		t.Errorf("Expected error for a missing start node")
+	}
+}
+
+// TestTraverseVisitor tests that visitors can prune and stop a traversal
+func TestTraverseVisitor(t *testing.T) {
+	g := newSocialGraph(t)
+
+	visits, _ := g.Traverse("me", TraversalOptions{
+		Direction: BothDirections,
+		Visitor: func(v Visit) VisitAction {
+			if v.Node.ID == "ann" {
+				return Prune
+			}
+			return Continue
+		},
+	})
+	if got := visitIDs(visits); !equalIDs(got, []string{"me", "ann", "eve"}) {
+		t.Errorf("Expected pruned visits [me ann eve], got %v", got)
+	}
+
+	visits, _ = g.Traverse("me", TraversalOptions{
+		Strategy: DepthFirst,
+		Visitor: func(v Visit) VisitAction {
+			if v.Node.ID == "bob" {
+				return Stop
+			}
+			return Continue
+		},
+	})
+	if got := visitIDs(visits); !equalIDs(got, []string{"me", "ann", "bob"}) {
+		t.Errorf("Expected stopped visits [me ann bob], got %v", got)
+	}
+}
+
+// TestTraverseDepthFirstOrder tests that depth-first traversal matches DFS
+func TestTraverseDepthFirstOrder(t *testing.T) {
+	g := newDiamond(t)
+
+	visits, _ := g.Traverse("A", TraversalOptions{Strategy: DepthFirst})
+	dfs, _ := g.DFS("A")
+	if got := visitIDs(visits); !equalIDs(got, dfs) {
+		t.Errorf("Expected depth-first visits %v, got %v", dfs, got)
+	}
+}
@@ -0,0 +1,253 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTxDone is returned when a transaction is used after it was committed or
+// rolled back.
+var ErrTxDone = errors.New("transaction has already been committed or rolled back")
+
+// Tx is a transaction over a graph, created by Graph.Begin. Reads through a
+// Tx observe its own uncommitted writes. A Tx must be finished with exactly
+// one call to Commit or Rollback. Afterwards its reads and writes fail with
+// ErrTxDone, and reads that cannot fail return zero values. Rollback
+// only fails if the graph was left inconsistent; the transaction is finished
+// either way.
+type Tx interface {
+	Reader
+	Writer
+	Commit() error
+	Rollback() error
+}
+
+// txImpl applies mutations to the live store while holding the graph's write
+// lock, and records for each one a closure that reverses it. Rollback runs
+// the closures in reverse order. The store is not embedded, so that every
+// read goes through a method that checks done first.
+type txImpl struct {
+	store *store
+	g     *graphImpl
+	undo  []func() error
+	done  bool
+}
+
+func (t *txImpl) Commit() error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.finish()
+	return nil
+}
+
+func (t *txImpl) Rollback() error {
+	if t.done {
+		return ErrTxDone
+	}
+	// Each undo step restores a state the graph was in before, so it can
+	// only fail if the store itself is inconsistent. The remaining steps
+	// still run and the lock is still released, so that the graph stays
+	// usable.
+	var errs []error
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		if err := t.undo[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.finish()
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("could not roll back transaction: %w", err)
+	}
+	return nil
+}
+
+func (t *txImpl) finish() {
+	t.done = true
+	t.undo = nil
+	t.g.mu.Unlock()
+}
+
+func (t *txImpl) AddNode(node *Node) error {
+	if t.done {
+		return ErrTxDone
+	}
+	if err := t.store.addNode(node); err != nil {
+		return err
+	}
+	id := node.ID
+	t.undo = append(t.undo, func() error { return t.store.deleteNode(id) })
+	return nil
+}
+
+func (t *txImpl) UpdateNode(id string, patch map[string]any) (*Node, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	old := t.store.nodes.m[id]
+	node, err := t.store.updateNode(id, patch)
+	if err != nil {
+		return nil, err
+	}
+	t.undo = append(t.undo, func() error { return t.store.replaceNode(old) })
+	return node, nil
+}
+
+func (t *txImpl) SetNodeProperty(id, key string, value any) error {
+	if t.done {
+		return ErrTxDone
+	}
+	old := t.store.nodes.m[id]
+	if err := t.store.setNodeProperty(id, key, value); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, func() error { return t.store.replaceNode(old) })
+	return nil
+}
+
+func (t *txImpl) RemoveNodeProperty(id, key string) error {
+	if t.done {
+		return ErrTxDone
+	}
+	old := t.store.nodes.m[id]
+	if err := t.store.removeNodeProperty(id, key); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, func() error { return t.store.replaceNode(old) })
+	return nil
+}
+
+func (t *txImpl) DeleteNode(id string) error {
+	if t.done {
+		return ErrTxDone
+	}
+	node := t.store.nodes.m[id]
+	edges := t.store.incidentEdges(id)
+	if err := t.store.deleteNode(id); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, func() error {
+		if err := t.store.addNode(node); err != nil {
+			return err
+		}
+		return t.restoreEdges(edges)
+	})
+	return nil
+}
+
+func (t *txImpl) AddEdge(edge *Edge) error {
+	if t.done {
+		return ErrTxDone
+	}
+	if err := t.store.addEdge(edge); err != nil {
+		return err
+	}
+	id := edge.ID
+	t.undo = append(t.undo, func() error { return t.store.deleteEdgeByID(id) })
+	return nil
+}
+
+func (t *txImpl) UpdateEdge(id string, patch map[string]any) (*Edge, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	old := t.store.edges.m[id]
+	edge, err := t.store.updateEdge(id, patch)
+	if err != nil {
+		return nil, err
+	}
+	t.undo = append(t.undo, func() error { return t.store.replaceEdge(old) })
+	return edge, nil
+}
+
+func (t *txImpl) SetEdgeWeight(id string, weight float64) error {
+	if t.done {
+		return ErrTxDone
+	}
+	old := t.store.edges.m[id]
+	if err := t.store.setEdgeWeight(id, weight); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, func() error { return t.store.replaceEdge(old) })
+	return nil
+}
+
+func (t *txImpl) DeleteEdge(from, to string) error {
+	if t.done {
+		return ErrTxDone
+	}
+	edges := t.store.edgesBetween(from, to)
+	if err := t.store.deleteEdge(from, to); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, func() error { return t.restoreEdges(edges) })
+	return nil
+}
+
+func (t *txImpl) DeleteEdgeByID(id string) error {
+	if t.done {
+		return ErrTxDone
+	}
+	edge := t.store.edges.m[id]
+	if err := t.store.deleteEdgeByID(id); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, func() error { return t.store.insertEdge(edge) })
+	return nil
+}
+
+func (t *txImpl) CreateIndex(label, property string, kind IndexKind) error {
+	if t.done {
+		return ErrTxDone
+	}
+	if err := t.store.createIndex(label, property, kind); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, func() error { return t.store.dropIndex(label, property) })
+	return nil
+}
+
+func (t *txImpl) DropIndex(label, property string) error {
+	if t.done {
+		return ErrTxDone
+	}
+	idx, exists := t.store.indexes.m[indexKey{label, property}]
+	if err := t.store.dropIndex(label, property); err != nil {
+		return err
+	}
+	if exists {
+		t.undo = append(t.undo, func() error { return t.store.createIndex(label, property, idx.def.Kind) })
+	}
+	return nil
+}
+
+func (t *txImpl) CreateConstraint(c Constraint) error {
+	if t.done {
+		return ErrTxDone
+	}
+	if err := t.store.createConstraint(c); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, func() error { return t.store.dropConstraint(c) })
+	return nil
+}
+
+func (t *txImpl) DropConstraint(c Constraint) error {
+	if t.done {
+		return ErrTxDone
+	}
+	if err := t.store.dropConstraint(c); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, func() error { return t.store.createConstraint(c) })
+	return nil
+}
+
+func (t *txImpl) restoreEdges(edges []*Edge) error {
+	for _, edge := range edges {
+		if err := t.store.insertEdge(edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,486 @@
+package graph
+
+import (
+	"context"
+	"iter"
+)
+
+// The read half of Tx checks that the transaction is still open before
+// reading the store, whose lock a finished transaction no longer holds. Reads
+// that cannot fail return zero values instead of ErrTxDone.
+
+func (t *txImpl) Nodes() map[string]*Node {
+	if t.done {
+		return nil
+	}
+	return t.store.Nodes()
+}
+
+func (t *txImpl) OutEdges() map[string]map[string]*Edge {
+	if t.done {
+		return nil
+	}
+	return t.store.OutEdges()
+}
+
+func (t *txImpl) OutEdgesByID() map[string]map[string]*Edge {
+	if t.done {
+		return nil
+	}
+	return t.store.OutEdgesByID()
+}
+
+func (t *txImpl) Config() Config {
+	if t.done {
+		return Config{}
+	}
+	return t.store.Config()
+}
+
+func (t *txImpl) AllNodes() iter.Seq2[string, *Node] {
+	if t.done {
+		return func(yield func(string, *Node) bool) {}
+	}
+	return t.store.AllNodes()
+}
+
+func (t *txImpl) AllEdges() iter.Seq2[string, *Edge] {
+	if t.done {
+		return func(yield func(string, *Edge) bool) {}
+	}
+	return t.store.AllEdges()
+}
+
+func (t *txImpl) NodeCount() int {
+	if t.done {
+		return 0
+	}
+	return t.store.NodeCount()
+}
+
+func (t *txImpl) EdgeCount() int {
+	if t.done {
+		return 0
+	}
+	return t.store.EdgeCount()
+}
+
+func (t *txImpl) GetNode(id string) (*Node, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.GetNode(id)
+}
+
+func (t *txImpl) GetEdge(from, to string) (*Edge, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.GetEdge(from, to)
+}
+
+func (t *txImpl) GetEdgeByID(id string) (*Edge, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.GetEdgeByID(id)
+}
+
+func (t *txImpl) EdgesBetween(from, to string) ([]*Edge, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.EdgesBetween(from, to)
+}
+
+func (t *txImpl) Neighbors(id string, types ...string) ([]*Edge, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.Neighbors(id, types...)
+}
+
+func (t *txImpl) Incoming(id string, types ...string) ([]*Edge, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.Incoming(id, types...)
+}
+
+func (t *txImpl) NodesByLabel(label string) []*Node {
+	if t.done {
+		return nil
+	}
+	return t.store.NodesByLabel(label)
+}
+
+func (t *txImpl) EdgesByType(edgeType string) []*Edge {
+	if t.done {
+		return nil
+	}
+	return t.store.EdgesByType(edgeType)
+}
+
+func (t *txImpl) Indexes() []IndexDefinition {
+	if t.done {
+		return nil
+	}
+	return t.store.Indexes()
+}
+
+func (t *txImpl) FindNodes(label, property string, value any) ([]*Node, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.FindNodes(label, property, value)
+}
+
+func (t *txImpl) FindNodesInRange(label, property string, lo, hi any) ([]*Node, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.FindNodesInRange(label, property, lo, hi)
+}
+
+func (t *txImpl) FindNodesWithPrefix(label, property, prefix string) ([]*Node, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.FindNodesWithPrefix(label, property, prefix)
+}
+
+func (t *txImpl) Constraints() []Constraint {
+	if t.done {
+		return nil
+	}
+	return t.store.Constraints()
+}
+
+func (t *txImpl) DFS(start string) ([]string, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.DFS(start)
+}
+
+func (t *txImpl) DFSContext(ctx context.Context, start string) ([]string, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.DFSContext(ctx, start)
+}
+
+func (t *txImpl) BFS(start string) ([]string, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.BFS(start)
+}
+
+func (t *txImpl) BFSContext(ctx context.Context, start string) ([]string, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.BFSContext(ctx, start)
+}
+
+func (t *txImpl) ShortestPath(source, target string) ([]string, float64, error) {
+	if t.done {
+		return nil, 0, ErrTxDone
+	}
+	return t.store.ShortestPath(source, target)
+}
+
+func (t *txImpl) ShortestPathContext(ctx context.Context, source, target string) ([]string, float64, error) {
+	if t.done {
+		return nil, 0, ErrTxDone
+	}
+	return t.store.ShortestPathContext(ctx, source, target)
+}
+
+func (t *txImpl) AStar(source, target string, heuristic func(a, b *Node) float64) ([]string, float64, error) {
+	if t.done {
+		return nil, 0, ErrTxDone
+	}
+	return t.store.AStar(source, target, heuristic)
+}
+
+func (t *txImpl) AStarContext(ctx context.Context, source, target string, heuristic func(a, b *Node) float64) ([]string, float64, error) {
+	if t.done {
+		return nil, 0, ErrTxDone
+	}
+	return t.store.AStarContext(ctx, source, target, heuristic)
+}
+
+func (t *txImpl) BellmanFord(source string) (*PathTree, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.BellmanFord(source)
+}
+
+func (t *txImpl) BellmanFordContext(ctx context.Context, source string) (*PathTree, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.BellmanFordContext(ctx, source)
+}
+
+func (t *txImpl) AllPairsShortestPaths(alg AllPairsAlgorithm) (*DistanceMatrix, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.AllPairsShortestPaths(alg)
+}
+
+func (t *txImpl) AllPairsShortestPathsContext(ctx context.Context, alg AllPairsAlgorithm) (*DistanceMatrix, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.AllPairsShortestPathsContext(ctx, alg)
+}
+
+func (t *txImpl) ShortestPathTree(source string) (*PathTree, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.ShortestPathTree(source)
+}
+
+func (t *txImpl) ShortestPathTreeContext(ctx context.Context, source string) (*PathTree, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.ShortestPathTreeContext(ctx, source)
+}
+
+func (t *txImpl) KShortestPaths(source, target string, k int) ([]*Path, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.KShortestPaths(source, target, k)
+}
+
+func (t *txImpl) KShortestPathsContext(ctx context.Context, source, target string, k int) ([]*Path, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.KShortestPathsContext(ctx, source, target, k)
+}
+
+func (t *txImpl) BidirectionalShortestPath(source, target string) ([]string, float64, error) {
+	if t.done {
+		return nil, 0, ErrTxDone
+	}
+	return t.store.BidirectionalShortestPath(source, target)
+}
+
+func (t *txImpl) BidirectionalShortestPathContext(ctx context.Context, source, target string) ([]string, float64, error) {
+	if t.done {
+		return nil, 0, ErrTxDone
+	}
+	return t.store.BidirectionalShortestPathContext(ctx, source, target)
+}
+
+func (t *txImpl) TopologicalSort() ([]string, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.TopologicalSort()
+}
+
+func (t *txImpl) TopologicalSortContext(ctx context.Context) ([]string, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.TopologicalSortContext(ctx)
+}
+
+func (t *txImpl) TopologicalWaves() ([][]string, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.TopologicalWaves()
+}
+
+func (t *txImpl) TopologicalWavesContext(ctx context.Context) ([][]string, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.TopologicalWavesContext(ctx)
+}
+
+func (t *txImpl) IsDAG() bool {
+	if t.done {
+		return false
+	}
+	return t.store.IsDAG()
+}
+
+func (t *txImpl) IsDAGContext(ctx context.Context) (bool, error) {
+	if t.done {
+		return false, ErrTxDone
+	}
+	return t.store.IsDAGContext(ctx)
+}
+
+func (t *txImpl) LongestPath() (*Path, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.LongestPath()
+}
+
+func (t *txImpl) LongestPathContext(ctx context.Context) (*Path, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.LongestPathContext(ctx)
+}
+
+func (t *txImpl) FindCycle() (*Path, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.FindCycle()
+}
+
+func (t *txImpl) FindCycleContext(ctx context.Context) (*Path, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.FindCycleContext(ctx)
+}
+
+func (t *txImpl) HasCycle() bool {
+	if t.done {
+		return false
+	}
+	return t.store.HasCycle()
+}
+
+func (t *txImpl) HasCycleContext(ctx context.Context) (bool, error) {
+	if t.done {
+		return false, ErrTxDone
+	}
+	return t.store.HasCycleContext(ctx)
+}
+
+func (t *txImpl) SimpleCycles(limit int) ([]*Path, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.SimpleCycles(limit)
+}
+
+func (t *txImpl) SimpleCyclesContext(ctx context.Context, limit int) ([]*Path, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.SimpleCyclesContext(ctx, limit)
+}
+
+func (t *txImpl) StronglyConnectedComponents() (*Components, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.StronglyConnectedComponents()
+}
+
+func (t *txImpl) StronglyConnectedComponentsContext(ctx context.Context) (*Components, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.StronglyConnectedComponentsContext(ctx)
+}
+
+func (t *txImpl) Condensation() (Graph, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.Condensation()
+}
+
+func (t *txImpl) CondensationContext(ctx context.Context) (Graph, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.CondensationContext(ctx)
+}
+
+func (t *txImpl) WeaklyConnectedComponents() (*Components, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.WeaklyConnectedComponents()
+}
+
+func (t *txImpl) WeaklyConnectedComponentsContext(ctx context.Context) (*Components, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.WeaklyConnectedComponentsContext(ctx)
+}
+
+func (t *txImpl) SameComponent(a, b string) (bool, error) {
+	if t.done {
+		return false, ErrTxDone
+	}
+	return t.store.SameComponent(a, b)
+}
+
+func (t *txImpl) ComponentCount() int {
+	if t.done {
+		return 0
+	}
+	return t.store.ComponentCount()
+}
+
+func (t *txImpl) ComponentCountContext(ctx context.Context) (int, error) {
+	if t.done {
+		return 0, ErrTxDone
+	}
+	return t.store.ComponentCountContext(ctx)
+}
+
+func (t *txImpl) MinimumSpanningTree(alg SpanningAlgorithm) (*SpanningTree, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.MinimumSpanningTree(alg)
+}
+
+func (t *txImpl) MinimumSpanningTreeContext(ctx context.Context, alg SpanningAlgorithm) (*SpanningTree, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.MinimumSpanningTreeContext(ctx, alg)
+}
+
+func (t *txImpl) MinimumSpanningArborescence(root string) (*SpanningTree, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.MinimumSpanningArborescence(root)
+}
+
+func (t *txImpl) MinimumSpanningArborescenceContext(ctx context.Context, root string) (*SpanningTree, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.MinimumSpanningArborescenceContext(ctx, root)
+}
+
+func (t *txImpl) Traverse(start string, opts TraversalOptions) ([]Visit, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.Traverse(start, opts)
+}
+
+func (t *txImpl) TraverseContext(ctx context.Context, start string, opts TraversalOptions) ([]Visit, error) {
+	if t.done {
+		return nil, ErrTxDone
+	}
+	return t.store.TraverseContext(ctx, start, opts)
+}
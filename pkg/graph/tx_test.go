@@ -0,0 +1,205 @@
+package graph
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// TestTxCommit tests that committed writes become visible and are read back
+// inside the transaction
+func TestTxCommit(t *testing.T) {
+	g := NewGraph()
+
+	tx := g.Begin()
+	tx.AddNode(&Node{ID: "A"})
+	tx.AddNode(&Node{ID: "B"})
+	if err := tx.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 1.0}); err != nil {
+		t.Fatalf("Failed to add edge in transaction: %v", err)
+	}
+
+	// Reads inside the transaction see its own writes
+	if _, err := tx.GetEdgeByID("e1"); err != nil {
+		t.Errorf("Expected transaction to read its own edge: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if _, err := g.GetEdge("A", "B"); err != nil {
+		t.Errorf("Expected committed edge to be visible: %v", err)
+	}
+	if err := tx.Commit(); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Expected ErrTxDone on second commit, got %v", err)
+	}
+
+	// A finished transaction fails reads as it does writes
+	if _, err := tx.GetNode("A"); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Expected ErrTxDone on read after commit, got %v", err)
+	}
+	if n := tx.NodeCount(); n != 0 {
+		t.Errorf("Expected no nodes after commit, got %d", n)
+	}
+}
+
+// TestTxRollback tests that a rollback restores the graph, including nodes
+// deleted with their edges
+func TestTxRollback(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A", Labels: []string{"Person"}})
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e2", From: "B", To: "A", Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e3", From: "A", To: "A", Weight: 1.0})
+
+	tx := g.Begin()
+	tx.AddNode(&Node{ID: "C"})
+	tx.AddEdge(&Edge{ID: "e4", From: "C", To: "B"})
+	tx.DeleteNode("A")
+	tx.CreateIndex("", "name", HashIndex)
+	if _, err := tx.GetNode("A"); err == nil {
+		t.Error("Expected deleted node to be gone inside the transaction")
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+
+	if _, err := g.GetNode("C"); err == nil {
+		t.Error("Expected rolled back node to be absent")
+	}
+	for _, id := range []string{"e1", "e2", "e3"} {
+		if _, err := g.GetEdgeByID(id); err != nil {
+			t.Errorf("Expected edge '%s' to be restored: %v", id, err)
+		}
+	}
+	if people := g.NodesByLabel("Person"); len(people) != 1 {
+		t.Errorf("Expected label index to be restored, got %v", people)
+	}
+	if len(g.Indexes()) != 0 {
+		t.Errorf("Expected rolled back index to be absent, got %v", g.Indexes())
+	}
+	if err := tx.AddNode(&Node{ID: "D"}); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Expected ErrTxDone after rollback, got %v", err)
+	}
+}
+
+// TestUpdateRollsBackOnError tests that a failing batch is not half-applied
+func TestUpdateRollsBackOnError(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A"})
+
+	err := g.Update(func(tx Tx) error {
+		if err := tx.AddNode(&Node{ID: "B"}); err != nil {
+			return err
+		}
+		if err := tx.AddEdge(&Edge{ID: "e1", From: "A", To: "B"}); err != nil {
+			return err
+		}
+		return tx.AddEdge(&Edge{ID: "e2", From: "A", To: "missing"})
+	})
+	if err == nil {
+		t.Fatal("Expected Update to fail, got nil")
+	}
+	if _, err := g.GetNode("B"); err == nil {
+		t.Error("Expected node from failed batch to be rolled back")
+	}
+	if _, err := g.GetEdgeByID("e1"); err == nil {
+		t.Error("Expected edge from failed batch to be rolled back")
+	}
+
+	err = g.Update(func(tx Tx) error {
+		return tx.AddNode(&Node{ID: "B"})
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := g.GetNode("B"); err != nil {
+		t.Errorf("Expected committed node to be visible: %v", err)
+	}
+}
+
+// TestTxIsolation tests that concurrent readers do not observe uncommitted
+// writes
+func TestTxIsolation(t *testing.T) {
+	g := NewGraph()
+
+	tx := g.Begin()
+	tx.AddNode(&Node{ID: "A"})
+
+	// Readers need the read lock, which the transaction keeps from them
+	if g.(*graphImpl).mu.TryRLock() {
+		g.(*graphImpl).mu.RUnlock()
+		t.Fatal("Expected reader to wait for the transaction to finish")
+	}
+
+	found := make(chan bool)
+	go func() {
+		_, err := g.GetNode("A")
+		found <- err == nil
+	}()
+
+	tx.Commit()
+	if !<-found {
+		t.Error("Expected reader to see the committed node")
+	}
+}
+
+// TestTxRollbackFailure tests that a failed undo step is reported and still releases the graph
+func TestTxRollbackFailure(t *testing.T) {
+	g := NewGraph()
+
+	tx := g.Begin()
+	if err := tx.AddNode(&Node{ID: "A"}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	impl := tx.(*txImpl)
+	impl.undo = append(impl.undo, func() error { return errors.New("broken undo step") })
+
+	if err := tx.Rollback(); err == nil {
+		t.Errorf("Expected the failed undo step to be reported")
+	}
+	if !g.(*graphImpl).mu.TryLock() {
+		t.Fatal("Expected the write lock to be released")
+	}
+	g.(*graphImpl).mu.Unlock()
+	if _, err := g.GetNode("A"); err == nil {
+		t.Errorf("Expected the other undo steps to run")
+	}
+	if err := tx.Rollback(); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Expected ErrTxDone, got %v", err)
+	}
+}
+
+// TestTxRollbackLeavesSnapshots tests that reinserting an edge on rollback does
+// not write to the edge a snapshot shares. Run with -race to check.
+func TestTxRollbackLeavesSnapshots(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e", From: "A", To: "B", Weight: 1.0})
+	snap := g.Snapshot()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			if tree, err := snap.BellmanFord("A"); err != nil || tree.Distances["B"] != 1.0 {
+				t.Errorf("Expected distance 1 to B on snapshot, got %v (%v)", tree, err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			tx := g.Begin()
+			tx.DeleteEdgeByID("e")
+			tx.Rollback()
+		}
+	}()
+	wg.Wait()
+
+	if edge, err := g.GetEdgeByID("e"); err != nil || !edge.Directed {
+		t.Errorf("Expected rolled back edge to be directed, got %v (%v)", edge, err)
+	}
+}
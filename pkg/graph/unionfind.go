@@ -0,0 +1,86 @@
+package graph
+
+import "maps"
+
+// unionFind is a disjoint-set forest over node IDs, merging the smaller tree
+// into the larger so that no tree is deeper than log2 of its size. Like the
+// store's other containers it belongs to an epoch, and must be cloned before
+// it is written in a later one.
+//
+// root walks the tree without changing it, so that readers holding only the
+// read lock can share the forest; find also compresses the path it walks and
+// is for writers.
+type unionFind struct {
+	parent map[string]string
+	size   map[string]int
+	count  int
+	epoch  uint64
+}
+
+func newUnionFind(epoch uint64) *unionFind {
+	return &unionFind{
+		parent: make(map[string]string),
+		size:   make(map[string]int),
+		epoch:  epoch,
+	}
+}
+
+func (u *unionFind) clone(epoch uint64) *unionFind {
+	return &unionFind{
+		parent: maps.Clone(u.parent),
+		size:   maps.Clone(u.size),
+		count:  u.count,
+		epoch:  epoch,
+	}
+}
+
+// add puts id in a set of its own, unless it is already in one.
+func (u *unionFind) add(id string) {
+	if _, exists := u.parent[id]; exists {
+		return
+	}
+	u.parent[id] = id
+	u.size[id] = 1
+	u.count++
+}
+
+// remove takes id out of the forest. It must be in a set of its own.
+func (u *unionFind) remove(id string) {
+	if _, exists := u.parent[id]; !exists {
+		return
+	}
+	delete(u.parent, id)
+	delete(u.size, id)
+	u.count--
+}
+
+func (u *unionFind) root(id string) string {
+	for u.parent[id] != id {
+		id = u.parent[id]
+	}
+	return id
+}
+
+func (u *unionFind) find(id string) string {
+	root := u.root(id)
+	for id != root {
+		id, u.parent[id] = u.parent[id], root
+	}
+	return root
+}
+
+// union merges the sets of a and b, reporting whether they were apart.
+func (u *unionFind) union(a, b string) bool {
+	ra, rb := u.find(a), u.find(b)
+	if ra == rb {
+		return false
+	}
+	if u.size[ra] < u.size[rb] {
+		ra, rb = rb, ra
+	}
+	u.parent[rb] = ra
+	u.size[ra] += u.size[rb]
+	delete(u.size, rb)
+	u.count--
+	return true
+}
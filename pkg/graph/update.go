@@ -0,0 +1,107 @@
+package graph
+
+import "fmt"
+
+// Nodes and edges are never modified in place once stored: snapshots share
+// them with the live store, and readers only ever receive copies. An update
+// instead builds a modified copy and swaps it in, re-indexing the element.
+
+// updateNode merges patch into the properties of a node and returns a copy
+// of the updated node. A nil value in the patch removes that property.
+func (s *store) updateNode(id string, patch map[string]any) (*Node, error) {
+	return s.patchNode(id, func(node *Node) {
+		for key, value := range patch {
+			if value == nil {
+				delete(node.Properties, key)
+			} else {
+				node.Properties[key] = value
+			}
+		}
+	})
+}
+
+func (s *store) setNodeProperty(id, key string, value any) error {
+	_, err := s.patchNode(id, func(node *Node) { node.Properties[key] = value })
+	return err
+}
+
+func (s *store) removeNodeProperty(id, key string) error {
+	_, err := s.patchNode(id, func(node *Node) { delete(node.Properties, key) })
+	return err
+}
+
+// updateEdge merges patch into the properties of an edge and returns a copy
+// of the updated edge. A nil value in the patch removes that property.
+func (s *store) updateEdge(id string, patch map[string]any) (*Edge, error) {
+	return s.patchEdge(id, func(edge *Edge) {
+		for key, value := range patch {
+			if value == nil {
+				delete(edge.Properties, key)
+			} else {
+				edge.Properties[key] = value
+			}
+		}
+	})
+}
+
+func (s *store) setEdgeWeight(id string, weight float64) error {
+	_, err := s.patchEdge(id, func(edge *Edge) { edge.Weight = weight })
+	return err
+}
+
+// patchNode applies a change to a copy of a node and stores the copy in its
+// place, provided it satisfies every constraint.
+func (s *store) patchNode(id string, apply func(node *Node)) (*Node, error) {
+	old, exists := s.nodes.m[id]
+	if !exists {
+		return nil, fmt.Errorf("node %q not found", id)
+	}
+	node := old.clone()
+	if node.Properties == nil {
+		node.Properties = make(map[string]any)
+	}
+	apply(node)
+	if err := s.replaceNode(node); err != nil {
+		return nil, err
+	}
+	return node.clone(), nil
+}
+
+// patchEdge applies a change to a copy of an edge and stores the copy in its
+// place, provided it satisfies every constraint.
+func (s *store) patchEdge(id string, apply func(edge *Edge)) (*Edge, error) {
+	old, exists := s.edges.m[id]
+	if !exists {
+		return nil, fmt.Errorf("edge %q not found", id)
+	}
+	edge := old.clone()
+	if edge.Properties == nil {
+		edge.Properties = make(map[string]any)
+	}
+	apply(edge)
+	if err := s.replaceEdge(edge); err != nil {
+		return nil, err
+	}
+	return edge.clone(), nil
+}
+
+// replaceNode swaps a stored node for a new version with the same ID.
+func (s *store) replaceNode(node *Node) error {
+	if err := s.checkNode(node); err != nil {
+		return err
+	}
+	s.unindexNode(s.nodes.m[node.ID])
+	s.nodes.mut(s.epoch)[node.ID] = node
+	s.indexNode(node)
+	return nil
+}
+
+// replaceEdge swaps a stored edge for a new version with the same ID.
+func (s *store) replaceEdge(edge *Edge) error {
+	if err := s.checkEdge(edge); err != nil {
+		return err
+	}
+	s.removeEdge(s.edges.m[edge.ID])
+	s.linkEdge(edge)
+	return nil
+}
@@ -0,0 +1,167 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestUpdateNode tests merging a patch into node properties
+func TestUpdateNode(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A", Properties: map[string]any{"name": "Ann", "age": 30}})
+
+	node, err := g.UpdateNode("A", map[string]any{"age": 31, "name": nil, "city": "Leeds"})
+	if err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if node.Properties["age"] != 31 || node.Properties["city"] != "Leeds" {
+		t.Errorf("Unexpected properties after update: %v", node.Properties)
+	}
+	if _, exists := node.Properties["name"]; exists {
+		t.Error("Expected nil patch value to remove the property")
+	}
+
+	if _, err := g.UpdateNode("missing", map[string]any{"a": 1}); err == nil {
+		t.Error("Expected error when updating missing node, got nil")
+	}
+}
+
+// TestSetAndRemoveNodeProperty tests single-property updates and their effect
+// on indexes
+func TestSetAndRemoveNodeProperty(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"age": 30}})
+	g.CreateIndex("Person", "age", OrderedIndex)
+
+	if err := g.SetNodeProperty("A", "age", 45); err != nil {
+		t.Fatalf("SetNodeProperty failed: %v", err)
+	}
+	if nodes, _ := g.FindNodes("Person", "age", 30); len(nodes) != 0 {
+		t.Errorf("Expected old value to be unindexed, got %v", nodeIDs(nodes))
+	}
+	if nodes, _ := g.FindNodes("Person", "age", 45); len(nodes) != 1 {
+		t.Errorf("Expected new value to be indexed, got %v", nodeIDs(nodes))
+	}
+
+	if err := g.RemoveNodeProperty("A", "age"); err != nil {
+		t.Fatalf("RemoveNodeProperty failed: %v", err)
+	}
+	if nodes, _ := g.FindNodesInRange("Person", "age", 0, nil); len(nodes) != 0 {
+		t.Errorf("Expected removed property to be unindexed, got %v", nodeIDs(nodes))
+	}
+}
+
+// TestUpdateNodeConstraintViolation tests that a rejected update leaves the
+// node unchanged
+func TestUpdateNodeConstraintViolation(t *testing.T) {
+	g := NewGraph()
+	g.CreateConstraint(Constraint{Kind: UniqueConstraint, Label: "Person", Property: "email"})
+	g.AddNode(&Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"email": "a@example.com"}})
+	g.AddNode(&Node{ID: "B", Labels: []string{"Person"}, Properties: map[string]any{"email": "b@example.com"}})
+
+	err := g.SetNodeProperty("B", "email", "a@example.com")
+	var violation *ConstraintViolationError
+	if !errors.As(err, &violation) || violation.ConflictingID != "A" {
+		t.Fatalf("Expected unique violation against A, got %v", err)
+	}
+	node, _ := g.GetNode("B")
+	if node.Properties["email"] != "b@example.com" {
+		t.Errorf("Expected node to be unchanged, got %v", node.Properties)
+	}
+
+	// Re-setting a node's own value is not a conflict
+	if err := g.SetNodeProperty("A", "email", "a@example.com"); err != nil {
+		t.Errorf("Expected node to keep its own unique value: %v", err)
+	}
+}
+
+// TestUpdateEdgeAndWeight tests edge updates
+func TestUpdateEdgeAndWeight(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A"})
+	g.AddNode(&Node{ID: "B"})
+	g.AddNode(&Node{ID: "C"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "C", Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e2", From: "A", To: "B", Weight: 1.0})
+	g.AddEdge(&Edge{ID: "e3", From: "B", To: "C", Weight: 1.0})
+
+	if err := g.SetEdgeWeight("e1", 5.0); err != nil {
+		t.Fatalf("SetEdgeWeight failed: %v", err)
+	}
+	path, dist, err := g.ShortestPath("A", "C")
+	if err != nil || dist != 2.0 || len(path) != 3 {
+		t.Errorf("Expected path via B with distance 2, got %v %f (%v)", path, dist, err)
+	}
+
+	edge, err := g.UpdateEdge("e1", map[string]any{"toll": true})
+	if err != nil {
+		t.Fatalf("UpdateEdge failed: %v", err)
+	}
+	if edge.Properties["toll"] != true || edge.Weight != 5.0 {
+		t.Errorf("Unexpected edge after update: %+v", edge)
+	}
+	if err := g.SetEdgeWeight("missing", 1.0); err == nil {
+		t.Error("Expected error when updating missing edge, got nil")
+	}
+}
+
+// TestDefensiveCopies tests that callers cannot modify stored elements through
+// the pointers they pass in or get back
+func TestDefensiveCopies(t *testing.T) {
+	g := NewGraph()
+	input := &Node{ID: "A", Labels: []string{"Person"}, Properties: map[string]any{"age": 30}}
+	g.AddNode(input)
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 1.0, Properties: map[string]any{"k": "v"}})
+
+	input.Properties["age"] = 99
+	input.Labels[0] = "Robot"
+
+	node, _ := g.GetNode("A")
+	if node.Properties["age"] != 30 || !node.HasLabel("Person") {
+		t.Errorf("Expected stored node to ignore changes to the input, got %+v", node)
+	}
+
+	node.Properties["age"] = 50
+	edge, _ := g.GetEdgeByID("e1")
+	edge.Weight = 10
+	edge.Properties["k"] = "changed"
+	neighbors, _ := g.Neighbors("A")
+	neighbors[0].Weight = 20
+
+	node, _ = g.GetNode("A")
+	if node.Properties["age"] != 30 {
+		t.Errorf("Expected stored node to ignore changes to a returned copy, got %v", node.Properties)
+	}
+	edge, _ = g.GetEdgeByID("e1")
+	if edge.Weight != 1.0 || edge.Properties["k"] != "v" {
+		t.Errorf("Expected stored edge to ignore changes to returned copies, got %+v", edge)
+	}
+}
+
+// TestUpdateRollbackAndSnapshot tests that updates are undone by a rollback
+// and invisible to earlier snapshots
+func TestUpdateRollbackAndSnapshot(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(&Node{ID: "A", Properties: map[string]any{"age": 30}})
+	g.AddNode(&Node{ID: "B"})
+	g.AddEdge(&Edge{ID: "e1", From: "A", To: "B", Weight: 1.0})
+	snap := g.Snapshot()
+
+	tx := g.Begin()
+	tx.SetNodeProperty("A", "age", 31)
+	tx.SetEdgeWeight("e1", 2.0)
+	tx.Rollback()
+
+	node, _ := g.GetNode("A")
+	edge, _ := g.GetEdgeByID("e1")
+	if node.Properties["age"] != 30 || edge.Weight != 1.0 {
+		t.Errorf("Expected rollback to restore node and edge, got %v and %f", node.Properties, edge.Weight)
+	}
+
+	g.UpdateNode("A", map[string]any{"age": 32})
+	node, _ = snap.GetNode("A")
+	if node.Properties["age"] != 30 {
+		t.Errorf("Expected snapshot to keep the old value, got %v", node.Properties)
+	}
+}